@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"pond/pond"
+
+	"github.com/spf13/cobra"
+)
+
+var BuildOptimizer string
+
+// buildCmd represents the build command
+var buildCmd = &cobra.Command{
+	Use:   "build [path]",
+	Short: "Build, register and deploy contracts of a cosmwasm workspace",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := "."
+		if len(args) > 0 {
+			path = args[0]
+		}
+
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		err = pond.Build(path, BuildOptimizer)
+		check(err)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(buildCmd)
+	buildCmd.PersistentFlags().StringVar(&BuildOptimizer, "optimizer", "", "set optimizer image, defaults to the workspace or rust optimizer")
+}
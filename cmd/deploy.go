@@ -6,6 +6,13 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	DeployDryRun bool
+	DeployWatch  bool
+	DeployFrom   string
+	DeployVars   map[string]string
+)
+
 // deployCmd represents the deploy command
 var deployCmd = &cobra.Command{
 	Use:   "deploy [file]",
@@ -15,12 +22,22 @@ var deployCmd = &cobra.Command{
 		pond, err := pond.NewPond(LogLevel)
 		check(err)
 
-		// err = pond.DeployPlanfile(args[0])
-		err = pond.Deploy(args)
+		if DeployWatch {
+			err = pond.DeployWatch(args, DeployFrom, DeployVars)
+			check(err)
+			return
+		}
+
+		err = pond.Deploy(args, DeployFrom, DeployVars, DeployDryRun)
 		check(err)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(deployCmd)
+	deployCmd.PersistentFlags().BoolVar(&DeployDryRun, "dry-run", false, "print and simulate the deployment without broadcasting")
+	deployCmd.PersistentFlags().BoolVar(&DeployWatch, "watch", false, "deploy again on every change of the files, migrating contracts to new codes")
+	deployCmd.PersistentFlags().StringVar(&DeployFrom, "from", "", "deploy as the given account, overrides the plan sender (default deployer)")
+	deployCmd.PersistentFlags().StringToStringVar(&DeployVars, "set", nil, "plan variables, ex.: amount=1000000")
+	deployCmd.MarkFlagsMutuallyExclusive("dry-run", "watch")
 }
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"pond/pond"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	ExportTarget pond.Export
+	ExportSave   bool
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export deployment data",
+}
+
+var exportAddressesCmd = &cobra.Command{
+	Use:   "addresses",
+	Short: "Export contract addresses, code ids, denoms and chain urls",
+	Run: func(cmd *cobra.Command, args []string) {
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		err = pond.ExportAddresses(ExportTarget, ExportSave)
+		check(err)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportAddressesCmd)
+
+	exportAddressesCmd.PersistentFlags().StringVar(&ExportTarget.Format, "format", "json", "output format: env, json, ts or go")
+	exportAddressesCmd.PersistentFlags().StringVarP(&ExportTarget.Output, "output", "o", "", "output file, prints to stdout if not set")
+	exportAddressesCmd.PersistentFlags().StringVar(&ExportTarget.Package, "package", "pond", "package name of go files")
+	exportAddressesCmd.PersistentFlags().BoolVar(&ExportSave, "save", true, "regenerate the output file after every deployment")
+}
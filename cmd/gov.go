@@ -8,7 +8,15 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var VoteOption string
+var (
+	VoteOption        string
+	VoteDistribution  map[string]int
+	ProposalStatus    string
+	ProposalValidator int
+	DepositValidator  int
+	ProposalArgs      map[string]string
+	ProposalPrint     bool
+)
 
 // govCmd represents the gov command
 var govCmd = &cobra.Command{
@@ -22,27 +30,183 @@ var govSubmitCmd = &cobra.Command{
 	Short: "Submit gov proposal json",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		switch VoteOption {
-		case "yes", "no", "abstain", "veto", "":
-			break
-		default:
-			err := fmt.Errorf(
-				"vote option must be one of: yes, no, abstain, veto",
-			)
+		err := checkVoteOption(VoteOption, true)
+		check(err)
+
+		for option := range VoteDistribution {
+			err := checkVoteOption(option, false)
 			check(err)
 		}
 
 		pond, err := pond.NewPond(LogLevel)
 		check(err)
 
-		err = pond.SubmitProposal(args[0], VoteOption)
+		id, err := pond.SubmitProposal(args[0], VoteOption, VoteDistribution)
 		check(err)
+
+		fmt.Println(id)
 	},
 }
 
+// govNewCmd represents the gov new command
+var govNewCmd = &cobra.Command{
+	Use:   "new [template]",
+	Short: "Create and submit a proposal from a template",
+	Long: `Create and submit a proposal from a template.
+
+Available templates: params, community-pool-spend, store-code, instantiate,
+migrate, sudo, software-upgrade, cancel-upgrade, client-recovery`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkVoteOption(VoteOption, true)
+		check(err)
+
+		for option := range VoteDistribution {
+			err := checkVoteOption(option, false)
+			check(err)
+		}
+
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		data, err := pond.NewProposal(args[0], ProposalArgs)
+		check(err)
+
+		if ProposalPrint {
+			fmt.Println(string(data))
+			return
+		}
+
+		id, err := pond.SubmitProposalJson(data, VoteOption, VoteDistribution)
+		check(err)
+
+		fmt.Println(id)
+	},
+}
+
+// govListCmd represents the gov list command
+var govListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List proposals",
+	Run: func(cmd *cobra.Command, args []string) {
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		err = pond.ListProposals(ProposalStatus)
+		check(err)
+	},
+}
+
+// govShowCmd represents the gov show command
+var govShowCmd = &cobra.Command{
+	Use:   "show [id]",
+	Short: "Show proposal",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		err = pond.ShowProposal(args[0])
+		check(err)
+	},
+}
+
+// govVoteCmd represents the gov vote command
+var govVoteCmd = &cobra.Command{
+	Use:   "vote [id]",
+	Short: "Vote on proposal with one or all validators",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := checkVoteOption(VoteOption, false)
+		check(err)
+
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		err = pond.Vote(args[0], ProposalValidator, VoteOption)
+		check(err)
+	},
+}
+
+// govDepositCmd represents the gov deposit command
+var govDepositCmd = &cobra.Command{
+	Use:   "deposit [id] [amount]",
+	Short: "Deposit to proposal",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		err = pond.Deposit(args[0], DepositValidator, args[1])
+		check(err)
+	},
+}
+
+// govTallyCmd represents the gov tally command
+var govTallyCmd = &cobra.Command{
+	Use:   "tally [id]",
+	Short: "Print the tally of a proposal",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		err = pond.Tally(args[0])
+		check(err)
+	},
+}
+
+// govWaitCmd represents the gov wait command
+var govWaitCmd = &cobra.Command{
+	Use:   "wait [id]",
+	Short: "Wait for the end of the voting period and print the result",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		err = pond.WaitProposal(args[0])
+		check(err)
+	},
+}
+
+func checkVoteOption(option string, optional bool) error {
+	switch option {
+	case "yes", "no", "abstain", "veto":
+		return nil
+	case "":
+		if optional {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("vote option must be one of: yes, no, abstain, veto")
+}
+
 func init() {
-	govSubmitCmd.PersistentFlags().StringVar(&VoteOption, "vote", "", "vote option")
+	govSubmitCmd.PersistentFlags().StringVar(&VoteOption, "vote", "", "vote option of all validators")
+	govSubmitCmd.PersistentFlags().StringToIntVar(&VoteDistribution, "votes", nil, "vote distribution, ex.: yes=2,no=1")
+
+	govNewCmd.PersistentFlags().StringToStringVar(&ProposalArgs, "set", nil, "template arguments, ex.: contract=\"USK Controller\"")
+	govNewCmd.PersistentFlags().StringVar(&VoteOption, "vote", "", "vote option of all validators")
+	govNewCmd.PersistentFlags().StringToIntVar(&VoteDistribution, "votes", nil, "vote distribution, ex.: yes=2,no=1")
+	govNewCmd.PersistentFlags().BoolVar(&ProposalPrint, "print", false, "print the proposal instead of submitting it")
+
+	govListCmd.PersistentFlags().StringVar(&ProposalStatus, "status", "", "filter by status, ex.: voting_period, passed")
+
+	govVoteCmd.PersistentFlags().StringVar(&VoteOption, "option", "", "vote option")
+	govVoteCmd.PersistentFlags().IntVar(&ProposalValidator, "validator", 0, "node number of the validator, default all")
+	govVoteCmd.MarkPersistentFlagRequired("option")
+
+	govDepositCmd.PersistentFlags().IntVar(&DepositValidator, "validator", 1, "node number of the validator")
 
 	govCmd.AddCommand(govSubmitCmd)
+	govCmd.AddCommand(govNewCmd)
+	govCmd.AddCommand(govListCmd)
+	govCmd.AddCommand(govShowCmd)
+	govCmd.AddCommand(govVoteCmd)
+	govCmd.AddCommand(govDepositCmd)
+	govCmd.AddCommand(govTallyCmd)
+	govCmd.AddCommand(govWaitCmd)
 	rootCmd.AddCommand(govCmd)
 }
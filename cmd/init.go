@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"pond/pond"
@@ -25,10 +26,14 @@ var (
 	Empty         bool
 	ApiUrl        string
 	RpcUrl        string
+	Sources       map[string]string
 	KujiraVersion string
 	Binary        string
 	Horcrux       bool
 	Overrides     string
+	NodeVersions  map[string]string
+	NodeBinaries  map[string]string
+	Offline       bool
 )
 
 // initCmd represents the init command
@@ -97,6 +102,12 @@ var initCmd = &cobra.Command{
 			}
 		}
 
+		versions, err := parseNodeMap(NodeVersions, Nodes)
+		check(err)
+
+		binaries, err := parseNodeMap(NodeBinaries, Nodes)
+		check(err)
+
 		config := pond.Config{
 			Command:   "docker",
 			Binary:    Binary,
@@ -105,11 +116,15 @@ var initCmd = &cobra.Command{
 			ApiUrl:    ApiUrl,
 			RpcUrl:    RpcUrl,
 			Plans:     Contracts,
+			Sources:   Sources,
+			Offline:   Offline,
 			Chains: []chain.Config{{
-				Type:    "kujira",
-				TypeNum: 1,
-				Nodes:   Nodes,
-				Signers: signers,
+				Type:     "kujira",
+				TypeNum:  1,
+				Nodes:    Nodes,
+				Signers:  signers,
+				Versions: versions,
+				Binaries: binaries,
 			}},
 			Versions: globals.Versions,
 		}
@@ -118,10 +133,13 @@ var initCmd = &cobra.Command{
 			config.Versions["kujira"] = KujiraVersion
 		}
 
-		pond, _ := pond.NewPond(LogLevel)
-		pond.Init(
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		err = pond.Init(
 			config, Chains, overrides,
 		)
+		check(err)
 	},
 }
 
@@ -133,14 +151,19 @@ func init() {
 	initCmd.PersistentFlags().StringVar(&Namespace, "namespace", "teamkujira", "Set docker.io namespace")
 	initCmd.PersistentFlags().StringVar(&ListenAddress, "listen", "127.0.0.1", "Set listen address")
 	initCmd.PersistentFlags().StringVar(&ApiUrl, "api-url", "https://rest.cosmos.directory/kujira", "Set API URL")
+	initCmd.PersistentFlags().StringToStringVar(&Sources, "source", nil, "Set API URLs of code source schemes, ex.: harpoon-4=https://my.api.node")
 	initCmd.PersistentFlags().StringVar(&RpcUrl, "rpc-url", "https://rpc.cosmos.directory/kujira", "Set RPC URL")
 	initCmd.PersistentFlags().StringVar(&KujiraVersion, "kujira-version", "", "Set Kujira version")
 	initCmd.PersistentFlags().StringVar(&Binary, "binary", "", "Path to local Kujira binary")
 	initCmd.PersistentFlags().StringVar(&Overrides, "overrides", "", "Path to genesis overrides")
 	initCmd.PersistentFlags().BoolVar(&NoContracts, "no-contracts", false, "Don't deploy contracts on first start")
 	initCmd.PersistentFlags().BoolVar(&Empty, "empty", false, "Don't deploy contracts on first start")
+	initCmd.PersistentFlags().BoolVar(&Offline, "offline", false, "Use cached codes and static prices only, see pond registry fetch")
 	initCmd.PersistentFlags().BoolVar(&Horcrux, "horcrux", false, "Use horcrux remote signers")
 
+	initCmd.PersistentFlags().StringToStringVar(&NodeVersions, "node-version", nil, "Set Kujira version per node, ex.: 2=v1.1.0")
+	initCmd.PersistentFlags().StringToStringVar(&NodeBinaries, "node-binary", nil, "Set local Kujira binary per node, ex.: 2=/path/to/kujirad")
+
 	initCmd.PersistentFlags().MarkDeprecated("no-contracts", "please use '--empty instead'")
 
 	chains, err := templates.GetChains()
@@ -163,3 +186,23 @@ func init() {
 		),
 	)
 }
+
+// parseNodeMap converts node number keys, ex.: {"2": "v1.1.0"}
+func parseNodeMap(items map[string]string, nodes uint) (map[uint]string, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	result := map[uint]string{}
+
+	for key, value := range items {
+		num, err := strconv.ParseUint(key, 10, 32)
+		if err != nil || num < 1 || uint(num) > nodes {
+			return nil, fmt.Errorf("invalid node number: %s", key)
+		}
+
+		result[uint(num)] = value
+	}
+
+	return result, nil
+}
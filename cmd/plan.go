@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"pond/pond"
+
+	"github.com/spf13/cobra"
+)
+
+// planCmd represents the plan command
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Work with plan files",
+}
+
+var planLintCmd = &cobra.Command{
+	Use:   "lint [files]",
+	Short: "Check plan files without deploying them",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// linting needs neither docker nor running chains
+		pond, err := pond.NewOfflinePond(LogLevel)
+		check(err)
+
+		err = pond.LintPlans(args)
+		check(err)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(planCmd)
+	planCmd.AddCommand(planLintCmd)
+}
@@ -9,8 +9,10 @@ import (
 )
 
 var (
-	NewRegistryName   string
-	NewRegistrySource string
+	NewRegistryName     string
+	NewRegistrySource   string
+	NewRegistryChecksum string
+	ImportMerge         bool
 )
 
 // registryCmd represents the registry command
@@ -46,6 +48,10 @@ var updateRegistryCmd = &cobra.Command{
 			updates["source"] = NewRegistrySource
 		}
 
+		if NewRegistryChecksum != "" {
+			updates["checksum"] = NewRegistryChecksum
+		}
+
 		pond, err := pond.NewPond(LogLevel)
 		check(err)
 
@@ -79,7 +85,65 @@ var importRegistryCmd = &cobra.Command{
 		pond, err := pond.NewPond(LogLevel)
 		check(err)
 
-		err = pond.ImportRegistry(args[0])
+		err = pond.ImportRegistry(args[0], ImportMerge)
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var addRegistryCmd = &cobra.Command{
+	Use:   "add [name]",
+	Short: "Add registry item, use name@version for additional versions",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		err = pond.AddRegistry(args[0], NewRegistrySource, NewRegistryChecksum)
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var removeRegistryCmd = &cobra.Command{
+	Use:   "remove [name]",
+	Short: "Remove registry item",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		err = pond.RemoveRegistry(args[0])
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var verifyRegistryCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify checksums of all registry codes",
+	Run: func(cmd *cobra.Command, args []string) {
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		err = pond.VerifyRegistry()
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var fetchRegistryCmd = &cobra.Command{
+	Use:   "fetch",
+	Short: "Download and verify all registry codes for offline use",
+	Run: func(cmd *cobra.Command, args []string) {
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		err = pond.FetchRegistry()
 		if err != nil {
 			os.Exit(1)
 		}
@@ -91,9 +155,22 @@ func init() {
 
 	updateRegistryCmd.PersistentFlags().StringVar(&NewRegistryName, "name", "", "Update name")
 	updateRegistryCmd.PersistentFlags().StringVar(&NewRegistrySource, "source", "", "Update source")
+	updateRegistryCmd.PersistentFlags().StringVar(&NewRegistryChecksum, "checksum", "", "Update checksum")
 	registryCmd.AddCommand(updateRegistryCmd)
+
+	addRegistryCmd.PersistentFlags().StringVar(&NewRegistrySource, "source", "", "Set source")
+	addRegistryCmd.PersistentFlags().StringVar(&NewRegistryChecksum, "checksum", "", "Set checksum, required for https sources")
+	addRegistryCmd.MarkPersistentFlagRequired("source")
+	registryCmd.AddCommand(addRegistryCmd)
+	registryCmd.AddCommand(removeRegistryCmd)
+
 	registryCmd.AddCommand(exportRegistryCmd)
+
+	importRegistryCmd.PersistentFlags().BoolVar(&ImportMerge, "merge", false, "Add and overwrite codes instead of replacing the registry")
 	registryCmd.AddCommand(importRegistryCmd)
 
+	registryCmd.AddCommand(verifyRegistryCmd)
+	registryCmd.AddCommand(fetchRegistryCmd)
+
 	rootCmd.AddCommand(registryCmd)
 }
@@ -12,8 +12,11 @@ var startCmd = &cobra.Command{
 	Short: "Start pond environment",
 	// Long: ``,
 	Run: func(cmd *cobra.Command, args []string) {
-		pond, _ := pond.NewPond(LogLevel)
-		pond.Start()
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		err = pond.Start()
+		check(err)
 	},
 }
 
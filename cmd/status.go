@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"pond/pond"
+
+	"github.com/spf13/cobra"
+)
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Print chain heights and detect halted chains",
+	Run: func(cmd *cobra.Command, args []string) {
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		err = pond.Status()
+		check(err)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
@@ -12,7 +12,8 @@ var stopCmd = &cobra.Command{
 	Short: "Stop pond environment",
 	// Long:  ``,
 	Run: func(cmd *cobra.Command, args []string) {
-		pond, _ := pond.NewPond(LogLevel)
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
 		pond.Stop()
 	},
 }
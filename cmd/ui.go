@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"pond/pond"
+
+	"github.com/spf13/cobra"
+)
+
+var UiPort string
+
+// uiCmd represents the ui command
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Serve a local web dashboard",
+	Run: func(cmd *cobra.Command, args []string) {
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		err = pond.Ui(UiPort)
+		check(err)
+	},
+}
+
+func init() {
+	uiCmd.PersistentFlags().StringVar(&UiPort, "port", "10080", "listen port")
+
+	rootCmd.AddCommand(uiCmd)
+}
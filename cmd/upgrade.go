@@ -6,14 +6,18 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var UpgradeChecks string
+
 // upgradeCmd represents the upgrade command
 var upgradeCmd = &cobra.Command{
 	Use:   "upgrade",
 	Short: "Upgrade kujira-1",
 	// Long: ``,
 	Run: func(cmd *cobra.Command, args []string) {
-		pond, _ := pond.NewPond(LogLevel)
-		err := pond.Upgrade(Version, Binary)
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		err = pond.Upgrade(Version, Binary, UpgradeChecks)
 		check(err)
 	},
 }
@@ -23,4 +27,5 @@ func init() {
 
 	upgradeCmd.PersistentFlags().StringVar(&Binary, "binary", "", "Path to new local Kujira binary")
 	upgradeCmd.PersistentFlags().StringVar(&Version, "version", "", "New Kujira version")
+	upgradeCmd.PersistentFlags().StringVar(&UpgradeChecks, "check", "", "Path to checks yaml, compared before and after the upgrade")
 }
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"pond/pond"
+
+	"github.com/spf13/cobra"
+)
+
+var WatchFilter pond.WatchFilter
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Stream blocks, txs and events",
+	Run: func(cmd *cobra.Command, args []string) {
+		pond, err := pond.NewPond(LogLevel)
+		check(err)
+
+		err = pond.Watch(WatchFilter)
+		check(err)
+	},
+}
+
+func init() {
+	watchCmd.PersistentFlags().StringSliceVar(&WatchFilter.Chains, "chain", nil, "filter by chain id")
+	watchCmd.PersistentFlags().StringSliceVar(&WatchFilter.Contracts, "contract", nil, "filter by contract label or address")
+	watchCmd.PersistentFlags().StringSliceVar(&WatchFilter.Accounts, "account", nil, "filter by account name")
+	watchCmd.PersistentFlags().StringSliceVar(&WatchFilter.Types, "type", nil, "filter by event type, ex.: wasm, ibc, oracle, wasm-*")
+	watchCmd.PersistentFlags().BoolVar(&WatchFilter.Json, "json", false, "print json lines")
+
+	rootCmd.AddCommand(watchCmd)
+}
@@ -0,0 +1,160 @@
+package pond
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"pond/pond/globals"
+	"pond/utils"
+)
+
+var workspaceRegex = regexp.MustCompile(`(?m)^\s*\[workspace\]`)
+
+// Artifact is an optimized wasm file listed in artifacts/checksums.txt
+type Artifact struct {
+	Name     string // crate name, ex.: kujira_fin
+	Filename string
+	Checksum string
+}
+
+// Build compiles the contracts at path with the cosmwasm optimizer, then
+// registers the artifacts by their crate names and deploys them
+func (p *Pond) Build(path, image string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return p.error(err)
+	}
+
+	if image == "" {
+		image, err = p.getOptimizer(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	p.logger.Info().Str("path", path).Str("image", image).Msg("build contracts")
+
+	name := filepath.Base(path)
+
+	command := []string{
+		p.config.Command, "run", "--rm", "-v", path + ":/code",
+		"--mount", fmt.Sprintf("type=volume,source=%s_cache,target=/target", name),
+		"--mount", "type=volume,source=registry_cache,target=/usr/local/cargo/registry",
+		image,
+	}
+
+	err = utils.Run(p.logger, command)
+	if err != nil {
+		return err
+	}
+
+	artifacts, err := p.readArtifacts(filepath.Join(path, "artifacts"))
+	if err != nil {
+		return err
+	}
+
+	err = p.deployer.UseSender("")
+	if err != nil {
+		return err
+	}
+
+	err = p.deployer.UpdateDeployedCodes()
+	if err != nil {
+		return err
+	}
+
+	for _, artifact := range artifacts {
+		data, err := os.ReadFile(artifact.Filename)
+		if err != nil {
+			return p.error(err)
+		}
+
+		if !strings.EqualFold(utils.Sha256(data), artifact.Checksum) {
+			err := fmt.Errorf("checksum mismatch")
+			p.logger.Err(err).Str("file", artifact.Filename).Msg("")
+			return err
+		}
+
+		p.logger.Info().Str("name", artifact.Name).Msg("deploy artifact")
+
+		err = p.deployer.DeployWasm(artifact.Name, artifact.Filename, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = p.UpdateCodes()
+	if err != nil {
+		return err
+	}
+
+	return p.WriteExports()
+}
+
+// getOptimizer returns the workspace optimizer for cargo workspaces and the
+// rust optimizer for single contracts
+func (p *Pond) getOptimizer(path string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(path, "Cargo.toml"))
+	if err != nil {
+		return "", p.error(err)
+	}
+
+	image := globals.Optimizers["contract"]
+	if workspaceRegex.Match(data) {
+		image = globals.Optimizers["workspace"]
+	}
+
+	if runtime.GOARCH == "arm64" {
+		image += "-arm64"
+	}
+
+	return image, nil
+}
+
+// readArtifacts parses checksums.txt, ex.:
+// 5f2c...  kujira_fin.wasm
+func (p *Pond) readArtifacts(dir string) ([]Artifact, error) {
+	file, err := os.Open(filepath.Join(dir, "checksums.txt"))
+	if err != nil {
+		return nil, p.error(err)
+	}
+
+	defer file.Close()
+
+	artifacts := []Artifact{}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+
+		// arm64 builds are suffixed with their architecture
+		name := strings.TrimSuffix(fields[1], ".wasm")
+		name = strings.TrimSuffix(name, "-aarch64")
+
+		artifacts = append(artifacts, Artifact{
+			Name:     name,
+			Filename: filepath.Join(dir, fields[1]),
+			Checksum: strings.ToUpper(fields[0]),
+		})
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, p.error(err)
+	}
+
+	if len(artifacts) == 0 {
+		err := fmt.Errorf("no artifacts found")
+		return nil, p.error(err)
+	}
+
+	return artifacts, nil
+}
@@ -1,11 +1,9 @@
 package chain
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -31,16 +29,12 @@ type Chain struct {
 }
 
 type Config struct {
-	Type    string   `json:"type"`     // ex.: kujira
-	TypeNum uint     `json:"type_num"` // ex.: 1
-	Nodes   uint     `json:"nodes"`    // ex.: 2
-	Signers []string `json:"signers"`  // ex.: ["local", "horcrux"]
-}
-
-type Block struct {
-	Header struct {
-		Time time.Time `json:"time"`
-	} `json:"header"`
+	Type     string          `json:"type"`               // ex.: kujira
+	TypeNum  uint            `json:"type_num"`           // ex.: 1
+	Nodes    uint            `json:"nodes"`              // ex.: 2
+	Signers  []string        `json:"signers"`            // ex.: ["local", "horcrux"]
+	Versions map[uint]string `json:"versions,omitempty"` // ex.: {"2": "v1.1.0"}
+	Binaries map[uint]string `json:"binaries,omitempty"` // ex.: {"2": "/usr/bin/kujirad"}
 }
 
 func NewChain(
@@ -49,6 +43,7 @@ func NewChain(
 	// typeNum, numNodes, chainNum uint,
 	config Config,
 	chainNum uint,
+	offline bool,
 ) (Chain, error) {
 	chainId := fmt.Sprintf("%s-%d", config.Type, config.TypeNum)
 
@@ -75,10 +70,37 @@ func NewChain(
 			}
 		}
 
+		nodeNum := uint(i + 1)
+
+		// per node settings take precedence over the chain wide binary
+		version := globals.Versions[config.Type]
+		nodeBinary := binary
+
+		custom, found := config.Versions[nodeNum]
+		if found {
+			version = custom
+			nodeBinary = ""
+		}
+
+		custom, found = config.Binaries[nodeNum]
+		if found {
+			nodeBinary = custom
+		}
+
+		if version == "" && nodeBinary == "" {
+			err := fmt.Errorf("version not found")
+			logger.Err(err).Uint("node", nodeNum).Msg("")
+			return Chain{}, err
+		}
+
 		node, err := node.NewNode(
-			logger, command, binary, address,
-			config.Type, config.TypeNum, uint(i+1), chainNum, node.Config{
-				Signer: signer,
+			logger, command, nodeBinary, address,
+			config.Type, config.TypeNum, nodeNum, chainNum, node.Config{
+				Signer:  signer,
+				Version: version,
+				Image: fmt.Sprintf(
+					"docker.io/%s/%s:%s", namespace, config.Type, version,
+				),
 			},
 		)
 		if err != nil {
@@ -89,7 +111,9 @@ func NewChain(
 		chain.Nodes[i] = node
 
 		if chainId == "kujira-1" {
-			feeder, err := feeder.NewFeeder(logger, command, address, chainNum, uint(i+1))
+			feeder, err := feeder.NewFeeder(
+				logger, command, address, chainNum, nodeNum, offline,
+			)
 			if err != nil {
 				logger.Err(err).Msg("")
 				return Chain{}, err
@@ -223,159 +247,116 @@ func (c *Chain) UpdateGenesis(overrides []byte) error {
 func (c *Chain) GetHeight() (int64, error) {
 	c.logger.Debug().Msg("get height")
 
-	type Status struct {
-		SyncInfo struct {
-			LatestBlockHeight string `json:"latest_block_height"`
-		} `json:"sync_info"`
-		SyncInfoOld struct {
-			LatestBlockHeight string `json:"latest_block_height"`
-		} `json:"SyncInfo"`
-	}
-
-	var status Status
-
-	output, err := c.Nodes[0].Status()
+	state, err := c.Nodes[0].GetState()
 	if err != nil {
 		return -1, c.error(err)
 	}
 
-	err = json.Unmarshal(output, &status)
-	if err != nil {
-		return -1, c.error(err)
-	}
-
-	strHeight := status.SyncInfoOld.LatestBlockHeight
-	if strHeight == "" {
-		strHeight = status.SyncInfo.LatestBlockHeight
-	}
-
-	height, err := strconv.ParseInt(strHeight, 10, 64)
-	if err != nil {
-		return -1, c.error(err)
-	}
-
-	return height, nil
+	return state.Height, nil
 }
 
+// WaitBlocks waits for the given amount of blocks. If the height doesn't
+// change within StallTimeout, the chain is checked for a halt.
 func (c *Chain) WaitBlocks(amount int64) error {
 	c.logger.Debug().Int64("blocks", amount).Msg("wait")
 
 	current, err := c.GetHeight()
 	if err != nil {
-		return nil
+		return err
 	}
 
 	target := current + amount
+	last := current
+	updated := time.Now()
 
 	for current < target {
 		time.Sleep(time.Millisecond * 500)
 
 		current, err = c.GetHeight()
 		if err != nil {
-			return nil
+			return err
 		}
-	}
-
-	return nil
-}
-
-func (c *Chain) SubmitProposal(data []byte, option string) error {
-	node := c.Nodes[0]
 
-	filename, err := node.CreateTemp(data, "json")
-	if err != nil {
-		return err
-	}
-
-	args := []string{
-		"gov", "submit-proposal", filename,
-		"--from", "validator", "--gas", "auto", "--gas-adjustment", "1.5",
-	}
+		if current != last {
+			last = current
+			updated = time.Now()
+			continue
+		}
 
-	output, err := node.Tx(args)
-	if err != nil {
-		return err
-	}
+		if time.Since(updated) < StallTimeout {
+			continue
+		}
 
-	hash, err := utils.CheckTxResponse(output)
-	if err != nil {
-		return c.error(err)
-	}
+		failure, err := c.Check()
+		if err != nil {
+			return err
+		}
 
-	err = node.WaitForTx(hash)
-	if err != nil {
-		return err
-	}
+		if failure != nil {
+			return failure
+		}
 
-	if option == "" {
-		return nil
+		updated = time.Now()
 	}
 
-	// get the latest proposal
+	return nil
+}
 
-	args = []string{
-		// TODO: < sdk-50
-		"gov", "proposals", "--status", "voting_period", "--reverse", "--output", "json",
-		// "gov", "proposals", "--output", "json", "--page-reverse",
-	}
+// WaitForUpgrade waits until the chain halts for the upgrade at the given
+// height, any other halt is returned as error
+func (c *Chain) WaitForUpgrade(height int64) error {
+	var remain int64 = -1
 
-	output, err = node.Query(args)
+	current, err := c.GetHeight()
 	if err != nil {
-		fmt.Println(string(output))
 		return err
 	}
 
-	type ProposalResponse struct {
-		Proposals []struct {
-			Id string `json:"id"`
-		} `json:"proposals"`
-	}
+	updated := time.Now()
 
-	var response ProposalResponse
+	for current < height {
+		if height-current != remain {
+			remain = height - current
+			updated = time.Now()
 
-	err = json.Unmarshal(output, &response)
-	if err != nil {
-		return c.error(err)
-	}
-
-	if len(response.Proposals) == 0 {
-		err := fmt.Errorf("no proposal found")
-		return c.error(err)
-	}
+			c.logger.Info().
+				Int64("blocks", remain).
+				Msg("waiting for upgrade height")
+		}
 
-	proposal := response.Proposals[0].Id
+		time.Sleep(time.Millisecond * 300)
 
-	var wg sync.WaitGroup
+		current, err = c.GetHeight()
+		if err != nil {
+			return err
+		}
 
-	for i := range c.Nodes {
-		wg.Add(1)
+		if time.Since(updated) < StallTimeout {
+			continue
+		}
 
-		go func(i int) {
-			args := []string{
-				"gov", "vote", proposal, option, "--from", "validator",
-				"--gas", "auto", "--gas-adjustment", "1.5",
-			}
+		// the chain halts before the upgrade height is committed
+		failure, err := c.Check()
+		if err != nil {
+			return err
+		}
 
-			output, err := c.Nodes[i].Tx(args)
-			if err != nil {
-				wg.Done()
-				return
-			}
+		if failure == nil {
+			updated = time.Now()
+			continue
+		}
 
-			hash, err := utils.CheckTxResponse(output)
-			if err != nil {
-				wg.Done()
-				return
-			}
+		if failure.Reason != ReasonUpgradeNeeded {
+			return failure
+		}
 
-			c.Nodes[i].WaitForTx(hash)
+		c.logger.Info().
+			Int64("height", failure.Height).
+			Msg("upgrade height reached")
 
-			wg.Done()
-		}(i)
+		return nil
 	}
 
-	wg.Wait()
-
 	return nil
 }
 
@@ -412,36 +393,8 @@ func (c *Chain) WaitForNode(name string) error {
 	return nil
 }
 
-func (c *Chain) GetBlock(height int64) (Block, error) {
-	var block Block
-	// < sdk-50
-	var response struct {
-		Block Block `json:"block"`
-	}
-
-	node := c.Nodes[0]
-
-	args := []string{
-		// --output param only available > sdk-50
-		// "block", "--output", "json", "--type", "height", fmt.Sprint(height),
-		"block", fmt.Sprint(height),
-	}
-
-	output, err := node.Query(args)
-	if err != nil {
-		fmt.Println(string(output))
-		return block, err
-	}
-
-	err = json.Unmarshal(output, &response)
-	if err != nil {
-		return block, err
-	}
-
-	// < sdk-50
-	block = response.Block
-
-	return block, nil
+func (c *Chain) GetBlock(height int64) (node.Block, error) {
+	return c.Nodes[0].GetBlock(height)
 }
 
 func (c *Chain) GetBlockTime(interval int64) (time.Duration, error) {
@@ -461,7 +414,7 @@ func (c *Chain) GetBlockTime(interval int64) (time.Duration, error) {
 		return -1, c.error(err)
 	}
 
-	timestamp1 := block.Header.Time
+	timestamp1 := block.Time
 
 	if height > interval {
 		height = height - interval
@@ -475,7 +428,7 @@ func (c *Chain) GetBlockTime(interval int64) (time.Duration, error) {
 		return -1, c.error(err)
 	}
 
-	timestamp0 := block.Header.Time
+	timestamp0 := block.Time
 
 	blockTime := timestamp1.Sub(timestamp0) / time.Duration(interval)
 
@@ -3,8 +3,10 @@ package feeder
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 
+	"pond/pond/globals"
 	"pond/utils"
 
 	"github.com/rs/zerolog"
@@ -17,12 +19,19 @@ type Feeder struct {
 	Home    string
 	Port    string
 	IpAddr  string
+	Static  bool // serve globals.StaticPrices instead of exchange prices
+}
+
+type price struct {
+	Symbol string
+	Price  string
 }
 
 func NewFeeder(
 	logger zerolog.Logger,
 	command, address string,
 	chainNum, nodeNum uint,
+	static bool,
 ) (Feeder, error) {
 	name := fmt.Sprintf("feeder%d-%d", chainNum, nodeNum)
 	port := strconv.Itoa(int(100+chainNum*10+nodeNum)) + "71"
@@ -42,12 +51,17 @@ func NewFeeder(
 		Home:    home + "/.pond/" + name,
 		Port:    port,
 		IpAddr:  address,
+		Static:  static,
 	}
 
 	return feeder, nil
 }
 
 func (f *Feeder) Init(namespace string) error {
+	if f.Static {
+		return f.initStatic(namespace)
+	}
+
 	version, err := utils.GetVersion(f.logger, "feeder")
 	if err != nil {
 		return f.error(err)
@@ -73,12 +87,63 @@ func (f *Feeder) Init(namespace string) error {
 	return nil
 }
 
+// initStatic serves fixed prices with the nginx proxy image, so the oracle
+// works without access to any exchange
+func (f *Feeder) initStatic(namespace string) error {
+	version, err := utils.GetVersion(f.logger, "proxy")
+	if err != nil {
+		return f.error(err)
+	}
+
+	os.MkdirAll(f.Home, 0o755)
+
+	image := fmt.Sprintf("docker.io/%s/proxy:%s", namespace, version)
+
+	err = f.CreateContainer(image)
+	if err != nil {
+		return f.error(err)
+	}
+
+	config := struct {
+		Port   string
+		Prices []price
+	}{
+		Port: f.Port,
+	}
+
+	for symbol, value := range globals.StaticPrices {
+		config.Prices = append(config.Prices, price{symbol, value})
+	}
+
+	sort.Slice(config.Prices, func(i, j int) bool {
+		return config.Prices[i].Symbol < config.Prices[j].Symbol
+	})
+
+	src := "config/kujira/feeder-static.conf"
+	dst := fmt.Sprintf("%s/feeder.conf", f.Home)
+
+	err = utils.Template(src, dst, config)
+	if err != nil {
+		return f.error(err)
+	}
+
+	return nil
+}
+
 func (f *Feeder) CreateContainer(image string) error {
 	f.logger.Debug().Msg("create container")
 
+	volume := f.Home + ":/home/feeder"
+	args := []string{image, "price-feeder", "/home/feeder/config.toml"}
+
+	if f.Static {
+		volume = f.Home + ":/etc/nginx/conf.d"
+		args = []string{image}
+	}
+
 	command := []string{
 		f.Command, "container", "create", "--name", f.Name,
-		"--network-alias", f.Name, "-v", f.Home + ":/home/feeder",
+		"--network-alias", f.Name, "-v", volume,
 		"-p", fmt.Sprintf("%s:%s:%s", f.IpAddr, f.Port, f.Port),
 		"--log-opt", "max-size=10m",
 	}
@@ -87,7 +152,7 @@ func (f *Feeder) CreateContainer(image string) error {
 		command = append(command, []string{"--network", "pond"}...)
 	}
 
-	command = append(command, []string{image, "price-feeder", "/home/feeder/config.toml"}...)
+	command = append(command, args...)
 
 	return utils.Run(f.logger, command)
 }
@@ -0,0 +1,382 @@
+package chain
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"pond/pond/client"
+	"pond/utils"
+)
+
+// VoteOptions in the order they are assigned to validators
+var VoteOptions = []string{"yes", "no", "abstain", "veto"}
+
+// DistributeVotes returns the vote of every validator for a distribution
+// like {"yes": 2, "no": 1}. Remaining validators don't vote.
+func (c *Chain) DistributeVotes(votes map[string]int) ([]string, error) {
+	options := []string{}
+
+	for option := range votes {
+		if !contains(VoteOptions, option) {
+			err := fmt.Errorf("vote option must be one of: %s",
+				strings.Join(VoteOptions, ", "))
+			return nil, c.error(err)
+		}
+	}
+
+	for _, option := range VoteOptions {
+		for i := 0; i < votes[option]; i++ {
+			options = append(options, option)
+		}
+	}
+
+	if len(options) > len(c.Nodes) {
+		err := fmt.Errorf(
+			"%d votes for %d validators", len(options), len(c.Nodes),
+		)
+		return nil, c.error(err)
+	}
+
+	for len(options) < len(c.Nodes) {
+		options = append(options, "")
+	}
+
+	return options, nil
+}
+
+// SubmitProposal submits the proposal and votes with the validators, votes
+// holds the option of each validator, empty options are skipped. If the
+// initial deposit is too low, the missing amount is deposited before voting.
+// It returns the proposal id.
+func (c *Chain) SubmitProposal(data []byte, votes []string) (string, error) {
+	node := c.Nodes[0]
+
+	filename, err := node.CreateTemp(data, "json")
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{
+		"gov", "submit-proposal", filename,
+		"--from", "validator", "--gas", "auto", "--gas-adjustment", "1.5",
+		"--output", "json",
+	}
+
+	output, err := node.Tx(args)
+	if err != nil {
+		return "", err
+	}
+
+	hash, err := utils.CheckTxResponse(output)
+	if err != nil {
+		return "", c.error(err)
+	}
+
+	response, err := node.WaitForTx(hash)
+	if err != nil {
+		return "", err
+	}
+
+	id, found := response.Attribute("submit_proposal", "proposal_id")
+	if !found {
+		err := fmt.Errorf("proposal id not found")
+		return "", c.error(err)
+	}
+
+	c.logger.Info().Str("proposal", id).Msg("proposal submitted")
+
+	voting := false
+	for _, option := range votes {
+		if option != "" {
+			voting = true
+		}
+	}
+
+	if !voting {
+		return id, nil
+	}
+
+	err = c.CompleteDeposit(id)
+	if err != nil {
+		return id, err
+	}
+
+	return id, c.Votes(id, votes)
+}
+
+// CompleteDeposit deposits the amount missing to min_deposit, so the
+// proposal enters the voting period
+func (c *Chain) CompleteDeposit(id string) error {
+	api := c.Nodes[0].Client()
+
+	proposal, err := api.Proposal(id)
+	if err != nil {
+		return c.error(err)
+	}
+
+	if proposal.Status != "PROPOSAL_STATUS_DEPOSIT_PERIOD" {
+		return nil
+	}
+
+	minDeposit, err := api.MinDeposit()
+	if err != nil {
+		return c.error(err)
+	}
+
+	deposited := map[string]*big.Int{}
+	for _, coin := range proposal.TotalDeposit {
+		amount, ok := new(big.Int).SetString(coin.Amount, 10)
+		if !ok {
+			err := fmt.Errorf("invalid amount: %s", coin.Amount)
+			return c.error(err)
+		}
+		deposited[coin.Denom] = amount
+	}
+
+	missing := []string{}
+	for _, coin := range minDeposit {
+		amount, ok := new(big.Int).SetString(coin.Amount, 10)
+		if !ok {
+			err := fmt.Errorf("invalid amount: %s", coin.Amount)
+			return c.error(err)
+		}
+
+		total, found := deposited[coin.Denom]
+		if found {
+			amount.Sub(amount, total)
+		}
+
+		if amount.Sign() > 0 {
+			missing = append(missing, amount.String()+coin.Denom)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	amount := strings.Join(missing, ",")
+
+	c.logger.Info().
+		Str("proposal", id).
+		Str("amount", amount).
+		Msg("deposit missing amount")
+
+	return c.Deposit(0, id, amount)
+}
+
+// Vote votes with all validators
+func (c *Chain) Vote(proposal, option string) error {
+	votes := make([]string, len(c.Nodes))
+	for i := range votes {
+		votes[i] = option
+	}
+
+	return c.Votes(proposal, votes)
+}
+
+// Votes votes with every validator its option, empty options are skipped
+func (c *Chain) Votes(proposal string, votes []string) error {
+	var wg sync.WaitGroup
+
+	errs := make([]error, len(c.Nodes))
+
+	for i := range c.Nodes {
+		if i >= len(votes) || votes[i] == "" {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = c.VoteValidator(i, proposal, votes[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// VoteValidator votes with the validator of the node at the given index
+func (c *Chain) VoteValidator(index int, proposal, option string) error {
+	if index < 0 || index >= len(c.Nodes) {
+		err := fmt.Errorf("validator not found")
+		return c.error(err)
+	}
+
+	node := c.Nodes[index]
+
+	c.logger.Info().
+		Str("node", node.Moniker).
+		Str("proposal", proposal).
+		Str("option", option).
+		Msg("vote")
+
+	// the cli doesn't accept "veto"
+	if option == "veto" {
+		option = "no_with_veto"
+	}
+
+	args := []string{
+		"gov", "vote", proposal, option, "--from", "validator",
+		"--gas", "auto", "--gas-adjustment", "1.5", "--output", "json",
+	}
+
+	output, err := node.Tx(args)
+	if err != nil {
+		return err
+	}
+
+	hash, err := utils.CheckTxResponse(output)
+	if err != nil {
+		return c.error(err)
+	}
+
+	_, err = node.WaitForTx(hash)
+	return err
+}
+
+// Deposit deposits the amount with the validator of the node at the given
+// index
+func (c *Chain) Deposit(index int, proposal, amount string) error {
+	if index < 0 || index >= len(c.Nodes) {
+		err := fmt.Errorf("validator not found")
+		return c.error(err)
+	}
+
+	node := c.Nodes[index]
+
+	args := []string{
+		"gov", "deposit", proposal, amount, "--from", "validator",
+		"--gas", "auto", "--gas-adjustment", "1.5", "--output", "json",
+	}
+
+	output, err := node.Tx(args)
+	if err != nil {
+		return err
+	}
+
+	hash, err := utils.CheckTxResponse(output)
+	if err != nil {
+		return c.error(err)
+	}
+
+	_, err = node.WaitForTx(hash)
+	return err
+}
+
+func (c *Chain) GetProposals(status string) ([]client.Proposal, error) {
+	api := c.Nodes[0].Client()
+
+	proposals, err := api.Proposals(status)
+	if err != nil {
+		return nil, c.error(err)
+	}
+
+	sort.Slice(proposals, func(i, j int) bool {
+		a, _ := strconv.Atoi(proposals[i].Id)
+		b, _ := strconv.Atoi(proposals[j].Id)
+		return a < b
+	})
+
+	return proposals, nil
+}
+
+func (c *Chain) GetProposal(id string) (client.Proposal, error) {
+	api := c.Nodes[0].Client()
+
+	proposal, err := api.Proposal(id)
+	if err != nil {
+		return proposal, c.error(err)
+	}
+
+	return proposal, nil
+}
+
+func (c *Chain) GetTally(id string) (client.Tally, error) {
+	api := c.Nodes[0].Client()
+
+	tally, err := api.Tally(id)
+	if err != nil {
+		return tally, c.error(err)
+	}
+
+	return tally, nil
+}
+
+// WaitProposal waits until the proposal left the deposit and voting period
+// and returns it together with the events of its execution
+func (c *Chain) WaitProposal(id string) (client.Proposal, []client.Event, error) {
+	c.logger.Info().Str("proposal", id).Msg("wait for proposal")
+
+	api := c.Nodes[0].Client()
+
+	start, err := c.GetHeight()
+	if err != nil {
+		return client.Proposal{}, nil, err
+	}
+
+	var proposal client.Proposal
+
+	for {
+		proposal, err = api.Proposal(id)
+		if err != nil {
+			return proposal, nil, c.error(err)
+		}
+
+		if proposal.Status != "PROPOSAL_STATUS_DEPOSIT_PERIOD" &&
+			proposal.Status != "PROPOSAL_STATUS_VOTING_PERIOD" {
+			break
+		}
+
+		start, err = c.GetHeight()
+		if err != nil {
+			return proposal, nil, err
+		}
+
+		time.Sleep(time.Second)
+	}
+
+	end, err := c.GetHeight()
+	if err != nil {
+		return proposal, nil, err
+	}
+
+	// the result is emitted by the end blocker
+	events := []client.Event{}
+
+	for height := start; height <= end; height++ {
+		blockEvents, err := api.BlockEvents(height)
+		if err != nil {
+			return proposal, nil, c.error(err)
+		}
+
+		for _, event := range blockEvents {
+			if event.Type != "active_proposal" {
+				continue
+			}
+
+			for _, attribute := range event.Attributes {
+				if attribute.Key == "proposal_id" && attribute.Value == id {
+					events = append(events, event)
+					break
+				}
+			}
+		}
+	}
+
+	return proposal, events, nil
+}
@@ -18,17 +18,6 @@ func (c *Chain) Init(namespace string, overrides []byte) error {
 	// init process before other nodes
 	os.MkdirAll(c.Nodes[0].Home+"/config/gentx", 0o755)
 
-	version, found := globals.Versions[c.Type]
-	if !found {
-		err := fmt.Errorf("version not found")
-		c.logger.Err(err).
-			Str("type", c.Type).
-			Msg("")
-		return err
-	}
-
-	image := fmt.Sprintf("docker.io/%s/%s:%s", namespace, c.Type, version)
-
 	amount := 10_000_000_000_000
 
 	var wg sync.WaitGroup
@@ -50,7 +39,7 @@ func (c *Chain) Init(namespace string, overrides []byte) error {
 
 		go func(i int) {
 			if !c.Nodes[i].Local {
-				err := c.Nodes[i].CreateInitContainer(image)
+				err := c.Nodes[i].CreateInitContainer(c.Nodes[i].Image)
 				if err != nil {
 					wg.Done()
 					return
@@ -164,7 +153,7 @@ func (c *Chain) Init(namespace string, overrides []byte) error {
 		if !c.Nodes[i].Local {
 			wg.Add(1)
 			go func(i int) {
-				err := c.Nodes[i].CreateRunContainer(image)
+				err := c.Nodes[i].CreateRunContainer(c.Nodes[i].Image)
 				if err != nil {
 					wg.Done()
 					c.error(err)
@@ -188,8 +177,18 @@ func (c *Chain) Init(namespace string, overrides []byte) error {
 						continue
 					}
 
+					// local and container nodes can be mixed, so use the
+					// address reachable from the node's point of view
+					host := node.Host
+					if node.Local && !c.Nodes[i].Local {
+						host = "host.docker.internal"
+					}
+					if !node.Local && c.Nodes[i].Local {
+						host = node.IpAddr
+					}
+
 					peers = append(peers, fmt.Sprintf(
-						"%s@%s:%s", node.NodeId, node.Host, node.Ports.App,
+						"%s@%s:%s", node.NodeId, host, node.Ports.App,
 					))
 				}
 
@@ -0,0 +1,329 @@
+package chain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"pond/pond/chain/node"
+)
+
+// StallTimeout is the time without a new block until a chain is checked
+// for a halt
+var StallTimeout = time.Second * 30
+
+const (
+	ReasonBlockHash        = "block hash mismatch"
+	ReasonAppHash          = "app hash mismatch"
+	ReasonConsensusFailure = "consensus failure"
+	ReasonUpgradeNeeded    = "upgrade needed"
+	ReasonPanic            = "panic"
+	ReasonUnreachable      = "node unreachable"
+)
+
+// logPatterns are checked in order, the first match defines the reason
+var logPatterns = []struct {
+	Reason string
+	Regex  *regexp.Regexp
+}{
+	{ReasonUpgradeNeeded, regexp.MustCompile(`UPGRADE "[^"]+" NEEDED`)},
+	{ReasonAppHash, regexp.MustCompile(`wrong Block\.Header\.AppHash`)},
+	{ReasonConsensusFailure, regexp.MustCompile(`CONSENSUS FAILURE`)},
+	{ReasonPanic, regexp.MustCompile(`^panic:`)},
+}
+
+var logHeight = regexp.MustCompile(`height[:=\s]+(\d+)`)
+
+type Failure struct {
+	ChainId string
+	Height  int64
+	Reason  string
+	Nodes   []string
+	Message string
+}
+
+func (f *Failure) Error() string {
+	msg := fmt.Sprintf(
+		"%s: %s on %s", f.ChainId, f.Reason, strings.Join(f.Nodes, ", "),
+	)
+
+	if f.Height > 0 {
+		msg += fmt.Sprintf(" at height %d", f.Height)
+	}
+
+	if f.Message != "" {
+		msg += ": " + f.Message
+	}
+
+	return msg
+}
+
+// GetStates returns the latest state of all nodes, unreachable nodes are
+// returned separately
+func (c *Chain) GetStates() (map[string]node.State, []string) {
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+
+	states := map[string]node.State{}
+	unreachable := []string{}
+
+	for i := range c.Nodes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			state, err := c.Nodes[i].GetState()
+
+			mtx.Lock()
+			defer mtx.Unlock()
+
+			if err != nil {
+				unreachable = append(unreachable, c.Nodes[i].Moniker)
+				return
+			}
+
+			states[c.Nodes[i].Moniker] = state
+		}(i)
+	}
+
+	wg.Wait()
+
+	sort.Strings(unreachable)
+
+	return states, unreachable
+}
+
+// Check compares block and app hashes of all nodes and scans their logs
+// for known failure messages. It returns nil if nothing has been found.
+func (c *Chain) Check() (*Failure, error) {
+	c.logger.Debug().Msg("check chain")
+
+	failure := c.checkLogs()
+	if failure != nil {
+		return failure, nil
+	}
+
+	states, unreachable := c.GetStates()
+	if len(states) == 0 {
+		return &Failure{
+			ChainId: c.ChainId,
+			Reason:  ReasonUnreachable,
+			Nodes:   unreachable,
+		}, nil
+	}
+
+	failure, err := c.checkHashes(states)
+	if err != nil {
+		return nil, err
+	}
+
+	if failure != nil {
+		return failure, nil
+	}
+
+	if len(unreachable) > 0 {
+		return &Failure{
+			ChainId: c.ChainId,
+			Reason:  ReasonUnreachable,
+			Nodes:   unreachable,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+func (c *Chain) checkLogs() *Failure {
+	failures := map[string]*Failure{}
+
+	for i := range c.Nodes {
+		output, err := c.Nodes[i].Logs(200)
+		if err != nil {
+			continue
+		}
+
+		for _, line := range strings.Split(string(output), "\n") {
+			for _, pattern := range logPatterns {
+				if !pattern.Regex.MatchString(line) {
+					continue
+				}
+
+				failure, found := failures[pattern.Reason]
+				if !found {
+					failure = &Failure{
+						ChainId: c.ChainId,
+						Reason:  pattern.Reason,
+						Message: strings.TrimSpace(line),
+					}
+					failures[pattern.Reason] = failure
+				}
+
+				matches := logHeight.FindStringSubmatch(line)
+				if len(matches) == 2 {
+					height, _ := strconv.ParseInt(matches[1], 10, 64)
+					if failure.Height == 0 || height < failure.Height {
+						failure.Height = height
+					}
+				}
+
+				moniker := c.Nodes[i].Moniker
+				if !contains(failure.Nodes, moniker) {
+					failure.Nodes = append(failure.Nodes, moniker)
+				}
+
+				break
+			}
+		}
+	}
+
+	for _, pattern := range logPatterns {
+		failure, found := failures[pattern.Reason]
+		if found {
+			return failure
+		}
+	}
+
+	return nil
+}
+
+func (c *Chain) checkHashes(states map[string]node.State) (*Failure, error) {
+	if len(states) < 2 {
+		return nil, nil
+	}
+
+	var height int64 = -1
+	for _, state := range states {
+		if height == -1 || state.Height < height {
+			height = state.Height
+		}
+	}
+
+	if height < 1 {
+		return nil, nil
+	}
+
+	monikers := []string{}
+	for moniker := range states {
+		monikers = append(monikers, moniker)
+	}
+	sort.Strings(monikers)
+
+	diverged, reason, err := c.compareHeight(monikers, height)
+	if err != nil {
+		return nil, err
+	}
+
+	if diverged == nil {
+		return nil, nil
+	}
+
+	// hashes stay different once they diverged, so search for the first
+	// diverging height
+	low, high := int64(1), height
+	for low < high {
+		mid := (low + high) / 2
+
+		nodes, midReason, err := c.compareHeight(monikers, mid)
+		if err != nil {
+			return nil, err
+		}
+
+		if nodes == nil {
+			low = mid + 1
+			continue
+		}
+
+		high = mid
+		diverged = nodes
+		reason = midReason
+	}
+
+	return &Failure{
+		ChainId: c.ChainId,
+		Height:  low,
+		Reason:  reason,
+		Nodes:   diverged,
+	}, nil
+}
+
+// compareHeight returns the nodes not agreeing with the majority
+func (c *Chain) compareHeight(
+	monikers []string, height int64,
+) ([]string, string, error) {
+	blocks := map[string][]string{}
+	apps := map[string][]string{}
+
+	for _, moniker := range monikers {
+		for i := range c.Nodes {
+			if c.Nodes[i].Moniker != moniker {
+				continue
+			}
+
+			block, err := c.Nodes[i].GetBlock(height)
+			if err != nil {
+				return nil, "", err
+			}
+
+			blocks[block.Hash] = append(blocks[block.Hash], moniker)
+			apps[block.AppHash] = append(apps[block.AppHash], moniker)
+		}
+	}
+
+	if len(apps) > 1 {
+		return minority(apps), ReasonAppHash, nil
+	}
+
+	if len(blocks) > 1 {
+		return minority(blocks), ReasonBlockHash, nil
+	}
+
+	return nil, "", nil
+}
+
+func minority(groups map[string][]string) []string {
+	size := 0
+	for _, group := range groups {
+		if len(group) > size {
+			size = len(group)
+		}
+	}
+
+	// without a clear majority, all nodes are affected
+	majority := ""
+	for hash, group := range groups {
+		if len(group) != size {
+			continue
+		}
+
+		if majority != "" {
+			majority = ""
+			break
+		}
+
+		majority = hash
+	}
+
+	nodes := []string{}
+	for hash, group := range groups {
+		if majority == "" || hash != majority {
+			nodes = append(nodes, group...)
+		}
+	}
+
+	sort.Strings(nodes)
+
+	return nodes
+}
+
+func contains(list []string, item string) bool {
+	for _, value := range list {
+		if value == item {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,227 @@
+package chain
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"pond/pond/chain/node"
+)
+
+// hashes returns the block and app hash of a node at the given height
+type hashes func(height int64) (string, string)
+
+func same(height int64) (string, string) {
+	return fmt.Sprintf("B%d", height), fmt.Sprintf("A%d", height)
+}
+
+// forked returns hashes that differ from the given height on
+func forked(from int64, app bool) hashes {
+	return func(height int64) (string, string) {
+		block, apphash := same(height)
+		if height < from {
+			return block, apphash
+		}
+		if app {
+			return block, apphash + "X"
+		}
+		return block + "X", apphash
+	}
+}
+
+// newTestChain serves the blocks of each node by rpc
+func newTestChain(t *testing.T, nodes ...hashes) *Chain {
+	t.Helper()
+
+	c := &Chain{ChainId: "kujira-1"}
+
+	for i, hashes := range nodes {
+		hashes := hashes
+
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				height, err := strconv.ParseInt(r.URL.Query().Get("height"), 10, 64)
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				block, app := hashes(height)
+				fmt.Fprintf(w, `{"result":{"block_id":{"hash":%q},"block":{"header":{"app_hash":%q}}}}`, block, app)
+			},
+		))
+		t.Cleanup(server.Close)
+
+		c.Nodes = append(c.Nodes, node.Node{
+			Moniker: fmt.Sprintf("kujira1-%d", i+1),
+			RpcUrl:  server.URL,
+		})
+	}
+
+	return c
+}
+
+func states(height int64, monikers ...string) map[string]node.State {
+	result := map[string]node.State{}
+	for _, moniker := range monikers {
+		result[moniker] = node.State{Height: height}
+	}
+	return result
+}
+
+func TestCheckHashes(t *testing.T) {
+	tests := []struct {
+		name     string
+		nodes    []hashes
+		expected *Failure
+	}{
+		{
+			name:  "in sync",
+			nodes: []hashes{same, same, same},
+		},
+		{
+			name:  "app hash minority",
+			nodes: []hashes{same, same, forked(7, true)},
+			expected: &Failure{
+				ChainId: "kujira-1", Height: 7, Reason: ReasonAppHash,
+				Nodes: []string{"kujira1-3"},
+			},
+		},
+		{
+			name:  "block hash minority",
+			nodes: []hashes{forked(3, false), same, same},
+			expected: &Failure{
+				ChainId: "kujira-1", Height: 3, Reason: ReasonBlockHash,
+				Nodes: []string{"kujira1-1"},
+			},
+		},
+		{
+			name:  "no majority",
+			nodes: []hashes{same, forked(5, true)},
+			expected: &Failure{
+				ChainId: "kujira-1", Height: 5, Reason: ReasonAppHash,
+				Nodes: []string{"kujira1-1", "kujira1-2"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		c := newTestChain(t, test.nodes...)
+
+		monikers := []string{}
+		for _, node := range c.Nodes {
+			monikers = append(monikers, node.Moniker)
+		}
+
+		failure, err := c.checkHashes(states(20, monikers...))
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(failure, test.expected) {
+			t.Errorf("%s: got %+v, want %+v", test.name, failure, test.expected)
+		}
+	}
+}
+
+func TestCheckHashesSkipped(t *testing.T) {
+	c := newTestChain(t, same, forked(1, true))
+
+	// a single node has nothing to compare with
+	failure, err := c.checkHashes(states(20, "kujira1-1"))
+	if failure != nil || err != nil {
+		t.Errorf("got %v %v", failure, err)
+	}
+
+	// no blocks yet
+	failure, err = c.checkHashes(states(0, "kujira1-1", "kujira1-2"))
+	if failure != nil || err != nil {
+		t.Errorf("got %v %v", failure, err)
+	}
+}
+
+func TestMinority(t *testing.T) {
+	tests := []struct {
+		groups   map[string][]string
+		expected []string
+	}{
+		{
+			map[string][]string{"A": {"n1", "n2"}, "B": {"n3"}},
+			[]string{"n3"},
+		},
+		{
+			map[string][]string{"A": {"n1"}, "B": {"n2", "n4"}, "C": {"n3"}},
+			[]string{"n1", "n3"},
+		},
+		{
+			map[string][]string{"A": {"n2"}, "B": {"n1"}},
+			[]string{"n1", "n2"},
+		},
+		{
+			map[string][]string{"A": {"n1", "n2"}, "B": {"n3", "n4"}, "C": {"n5"}},
+			[]string{"n1", "n2", "n3", "n4", "n5"},
+		},
+	}
+
+	for _, test := range tests {
+		result := minority(test.groups)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("%v: got %v, want %v", test.groups, result, test.expected)
+		}
+	}
+}
+
+func TestCheckLogs(t *testing.T) {
+	logs := []string{
+		"INF committed state height=41\nERR CONFLICT wrong Block.Header.AppHash height=42\n",
+		"INF committed state height=41\nERR CONFLICT wrong Block.Header.AppHash height=43\n",
+		"INF committed state height=44\n",
+	}
+
+	c := &Chain{ChainId: "kujira-1"}
+
+	for i, log := range logs {
+		home := t.TempDir()
+
+		err := os.WriteFile(filepath.Join(home, "kujirad.log"), []byte(log), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		c.Nodes = append(c.Nodes, node.Node{
+			Moniker: fmt.Sprintf("kujira1-%d", i+1),
+			Local:   true,
+			Home:    home,
+		})
+	}
+
+	failure := c.checkLogs()
+	if failure == nil {
+		t.Fatal("no failure found")
+	}
+
+	if failure.Reason != ReasonAppHash || failure.Height != 42 ||
+		!reflect.DeepEqual(failure.Nodes, []string{"kujira1-1", "kujira1-2"}) {
+		t.Errorf("got %+v", failure)
+	}
+
+	// upgrades take precedence
+	err := os.WriteFile(
+		filepath.Join(c.Nodes[2].Home, "kujirad.log"),
+		[]byte(`ERR UPGRADE "v2" NEEDED at height: 45`), 0o644,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failure = c.checkLogs()
+	if failure == nil || failure.Reason != ReasonUpgradeNeeded || failure.Height != 45 {
+		t.Errorf("got %+v", failure)
+	}
+}
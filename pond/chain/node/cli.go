@@ -0,0 +1,112 @@
+package node
+
+import (
+	"encoding/json"
+
+	"pond/utils"
+)
+
+// Cli covers the subcommands that differ between cosmos-sdk versions.
+// Queries of status, blocks, proposals and codes go through the rpc and api,
+// they are the same for all versions. Implementations are stateless, so they
+// can be shared by all nodes of a chain.
+type Cli interface {
+	Sdk() string
+	Gentx(amount, chainId string) []string
+	AddGenesisAccount(address, amount string) []string
+	CollectGentxs() []string
+}
+
+// NewCli returns the adapter for the given cosmos-sdk version,
+// ex.: v0.47.5
+func NewCli(version string) Cli {
+	minor, ok := utils.SdkMinor(version)
+	if ok && minor < 47 {
+		return Cli46{}
+	}
+
+	return Cli47{}
+}
+
+// Cli returns the adapter matching the node's binary, detected on first use
+func (n *Node) Cli() Cli {
+	if n.cli != nil {
+		return n.cli
+	}
+
+	n.cli = Cli47{}
+
+	command := n.NewCommand([]string{
+		n.Binary, "version", "--long", "--output", "json",
+	})
+
+	output, err := utils.RunO(n.logger, command)
+	if err != nil {
+		n.logger.Debug().Msg("sdk version not detected")
+		return n.cli
+	}
+
+	var info struct {
+		SdkVersion string `json:"cosmos_sdk_version"`
+	}
+
+	err = json.Unmarshal(output, &info)
+	if err != nil {
+		n.logger.Debug().Msg("sdk version not detected")
+		return n.cli
+	}
+
+	n.cli = NewCli(info.SdkVersion)
+
+	n.logger.Debug().
+		Str("version", info.SdkVersion).
+		Str("cli", n.cli.Sdk()).
+		Msg("sdk version detected")
+
+	return n.cli
+}
+
+// Cli46 handles binaries < sdk-47, genesis commands are top level commands
+type Cli46 struct{}
+
+func (Cli46) Sdk() string {
+	return "sdk-46"
+}
+
+func (Cli46) Gentx(amount, chainId string) []string {
+	return []string{
+		"gentx", "validator", "--keyring-backend", "test",
+		amount, "--chain-id", chainId, "--output", "json",
+	}
+}
+
+func (Cli46) AddGenesisAccount(address, amount string) []string {
+	return []string{"add-genesis-account", address, amount}
+}
+
+func (Cli46) CollectGentxs() []string {
+	return []string{"collect-gentxs"}
+}
+
+// Cli47 handles binaries >= sdk-47, including sdk-50. Genesis commands are
+// subcommands of genesis.
+type Cli47 struct{}
+
+func (Cli47) Sdk() string {
+	return "sdk-47"
+}
+
+func (Cli47) Gentx(amount, chainId string) []string {
+	return []string{
+		"genesis", "gentx", "validator", "--keyring-backend", "test",
+		amount, "--chain-id", chainId, "--output", "json",
+	}
+}
+
+func (Cli47) AddGenesisAccount(address, amount string) []string {
+	return []string{"genesis", "add-genesis-account", address, amount}
+}
+
+func (Cli47) CollectGentxs() []string {
+	return []string{"genesis", "collect-gentxs"}
+}
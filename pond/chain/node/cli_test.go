@@ -0,0 +1,42 @@
+package node
+
+import "testing"
+
+func TestNewCli(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{"v0.46.15", "sdk-46"},
+		{"0.45.16", "sdk-46"},
+		{"v0.47.5", "sdk-47"},
+		{"v0.50.6", "sdk-47"},
+		{"v1.0.0", "sdk-47"},
+		{"", "sdk-47"},
+		{"unknown", "sdk-47"},
+	}
+
+	for _, test := range tests {
+		result := NewCli(test.version).Sdk()
+		if result != test.expected {
+			t.Errorf("NewCli(%q) = %s, want %s", test.version, result, test.expected)
+		}
+	}
+}
+
+func TestCliGenesisCommands(t *testing.T) {
+	legacy := Cli46{}.AddGenesisAccount("kujira1a", "1ukuji")
+	if legacy[0] != "add-genesis-account" {
+		t.Errorf("got %v", legacy)
+	}
+
+	current := Cli47{}.AddGenesisAccount("kujira1a", "1ukuji")
+	if current[0] != "genesis" || current[1] != "add-genesis-account" {
+		t.Errorf("got %v", current)
+	}
+
+	collect := Cli46{}.CollectGentxs()
+	if collect[0] != "collect-gentxs" {
+		t.Errorf("got %v", collect)
+	}
+}
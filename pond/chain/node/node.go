@@ -2,6 +2,7 @@ package node
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,6 +14,7 @@ import (
 	"github.com/rs/zerolog"
 
 	"pond/pond/chain/node/signer"
+	"pond/pond/client"
 	"pond/pond/globals"
 	"pond/utils"
 )
@@ -36,9 +38,11 @@ type Ports struct {
 
 type Node struct {
 	logger    zerolog.Logger
+	cli       Cli
 	initState bool
 	Local     bool
 	Image     string        `json:"-"`        // ex.: docker.io/teamkujira/kujira:v0.8.4
+	Version   string        `json:"version"`  // ex.: v0.8.4 or /usr/bin/kujirad
 	Command   string        `json:"-"`        // ex.: docker
 	Binary    string        `json:"-"`        // ex.: kujirad or /usr/bin/kujirad
 	Type      string        `json:"-"`        // ex.: kujira
@@ -64,7 +68,22 @@ type Node struct {
 }
 
 type Config struct {
-	Signer string
+	Signer  string
+	Version string // ex.: v0.8.4
+	Image   string // ex.: docker.io/teamkujira/kujira:v0.8.4
+}
+
+type State struct {
+	Height  int64
+	Hash    string
+	AppHash string
+}
+
+type Block struct {
+	Height  int64
+	Hash    string
+	AppHash string
+	Time    time.Time
 }
 
 func NewNode(
@@ -111,6 +130,8 @@ func NewNode(
 		Ports:    ports,
 		Mnemonic: mnemonic,
 		Command:  command,
+		Image:    config.Image,
+		Version:  config.Version,
 		Binary:   globals.Chains[chainType].Command,
 		Denom:    globals.Chains[chainType].Denom,
 		AppUrl:   "tcp://" + address + ":" + ports.App,
@@ -123,6 +144,7 @@ func NewNode(
 	if binary != "" {
 		node.Local = true
 		node.Binary = binary
+		node.Version = binary
 	}
 
 	var feeder string
@@ -279,10 +301,12 @@ func (n *Node) AddGenesisAccountsContainer(accounts []Account) error {
 	}
 
 	command = append(command, []string{n.Moniker, "bash", "-c"}...)
+	add := n.Cli().AddGenesisAccount("$address", "${!address}")
+
 	command = append(command, fmt.Sprintf(
 		`for address in %s; do \
-			%s genesis add-genesis-account $address ${!address}
-		done`, strings.Join(addresses, " "), n.Binary,
+			%s %s
+		done`, strings.Join(addresses, " "), n.Binary, strings.Join(add, " "),
 	))
 
 	return utils.Run(n.logger, command)
@@ -292,10 +316,11 @@ func (n *Node) AddGenesisAccountsLocal(accounts []Account) error {
 	n.logger.Debug().Msg("add genesis accounts")
 
 	for _, account := range accounts {
-		command := []string{
-			n.Binary, "--home", n.Home, "genesis", "add-genesis-account",
-			account.Address, fmt.Sprintf("%d%s", account.Amount, n.Denom),
-		}
+		command := append([]string{n.Binary, "--home", n.Home},
+			n.Cli().AddGenesisAccount(
+				account.Address, fmt.Sprintf("%d%s", account.Amount, n.Denom),
+			)...,
+		)
 
 		err := utils.Run(n.logger, command)
 		if err != nil {
@@ -313,10 +338,9 @@ func (n *Node) AddGenesisAccount(address string, amount int) error {
 
 	// TODO: if init is too slow, add '-d' for containers
 	// n.Command, "exec", "--user", n.Type, "-d", n.Moniker,
-	command := n.NewCommand([]string{
-		n.Binary, "genesis", "add-genesis-account", address,
-		strconv.Itoa(amount) + n.Denom,
-	})
+	command := n.NewCommand(append([]string{n.Binary},
+		n.Cli().AddGenesisAccount(address, strconv.Itoa(amount)+n.Denom)...,
+	))
 
 	return utils.Run(n.logger, command)
 }
@@ -324,11 +348,9 @@ func (n *Node) AddGenesisAccount(address string, amount int) error {
 func (n *Node) CreateGentx(amount int) error {
 	n.logger.Debug().Msg("create gentx")
 
-	command := n.NewCommand([]string{
-		n.Binary, "genesis", "gentx", "validator", "--keyring-backend", "test",
-		strconv.Itoa(amount) + n.Denom, "--chain-id", n.ChainId,
-		"--output", "json",
-	})
+	command := n.NewCommand(append([]string{n.Binary},
+		n.Cli().Gentx(strconv.Itoa(amount)+n.Denom, n.ChainId)...,
+	))
 
 	err := utils.Run(n.logger, command)
 	if err != nil {
@@ -388,9 +410,9 @@ func (n *Node) CreateGentx(amount int) error {
 
 func (n *Node) CollectGentxs() error {
 	n.logger.Debug().Msg("collect gentxs")
-	command := n.NewCommand([]string{
-		n.Binary, "genesis", "collect-gentxs",
-	})
+	command := n.NewCommand(append([]string{n.Binary},
+		n.Cli().CollectGentxs()...,
+	))
 
 	return utils.Run(n.logger, command)
 }
@@ -703,17 +725,89 @@ func (n *Node) Status() ([]byte, error) {
 	return utils.RunO(n.logger, command)
 }
 
-func (n *Node) WaitForTx(hash string) error {
+// Client returns a client for the node's rpc and api endpoints
+func (n *Node) Client() client.Client {
+	return client.NewClient(n.logger, n.RpcUrl, n.ApiUrl)
+}
+
+func (n *Node) GetState() (State, error) {
+	c := n.Client()
+
+	status, err := c.Status()
+	if err != nil {
+		return State{}, err
+	}
+
+	return State{
+		Height:  status.Height,
+		Hash:    status.Hash,
+		AppHash: status.AppHash,
+	}, nil
+}
+
+func (n *Node) GetBlock(height int64) (Block, error) {
+	c := n.Client()
+
+	block, err := c.Block(height)
+	if err != nil {
+		return Block{}, n.error(err)
+	}
+
+	return Block{
+		Height:  block.Height,
+		Hash:    block.Hash,
+		AppHash: block.AppHash,
+		Time:    block.Time,
+	}, nil
+}
+
+// Logs returns the last lines of the node output
+func (n *Node) Logs(lines int) ([]byte, error) {
+	if !n.Local {
+		// only use logs since the last start, containers keep their logs
+		command := []string{
+			n.Command, "inspect", "--format", "{{ .State.StartedAt }}", n.Moniker,
+		}
+
+		output, err := utils.RunO(n.logger, command)
+		if err != nil {
+			return nil, err
+		}
+
+		command = []string{
+			n.Command, "logs", "--tail", strconv.Itoa(lines),
+			"--since", strings.TrimSpace(string(output)), n.Moniker,
+		}
+
+		return utils.RunC(n.logger, command)
+	}
+
+	data, err := os.ReadFile(filepath.Join(n.Home, "kujirad.log"))
+	if err != nil {
+		return nil, n.error(err)
+	}
+
+	parts := strings.Split(string(data), "\n")
+	if len(parts) > lines {
+		parts = parts[len(parts)-lines:]
+	}
+
+	return []byte(strings.Join(parts, "\n")), nil
+}
+
+// WaitForTx waits until the tx is included in a block and returns its
+// response including the events
+func (n *Node) WaitForTx(hash string) (client.TxResponse, error) {
 	n.logger.Debug().Str("hash", hash).Msg("wait for tx")
 
 	cycles := 10
 	interval := time.Second * 1
 
-	args := []string{"tx", hash}
+	c := n.Client()
 
 	var (
-		err  error
-		data []byte
+		err      error
+		response client.TxResponse
 	)
 
 	for i := 0; i < cycles; i++ {
@@ -722,18 +816,19 @@ func (n *Node) WaitForTx(hash string) error {
 			Msg("query tx")
 		time.Sleep(interval)
 
-		data, err = n.Query(args)
-		if err == nil {
-			_, err := utils.CheckTxResponse(data)
-			if err != nil {
-				return n.error(err)
-			}
+		response, err = c.Tx(hash)
+		if err != nil {
+			continue
+		}
 
-			return nil
+		if response.Code != 0 {
+			return response, n.error(errors.New(response.RawLog))
 		}
+
+		return response, nil
 	}
 
-	return err
+	return response, n.error(err)
 }
 
 func (n *Node) GetPid() (string, error) {
@@ -0,0 +1,277 @@
+package pond
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Checks struct {
+	Params    []string        `yaml:"params"`    // ex.: ["oracle", "gov"]
+	Balances  []string        `yaml:"balances"`  // ex.: ["deployer", "test0"]
+	Contracts []ContractCheck `yaml:"contracts"` // smart queries
+	Expect    []string        `yaml:"expect"`    // ex.: ["params/gov/*"]
+}
+
+type ContractCheck struct {
+	Name     string `yaml:"name"`     // ex.: usk-config
+	Contract string `yaml:"contract"` // ex.: USK Controller
+	Query    string `yaml:"query"`    // ex.: {"config":{}}
+}
+
+// Snapshot maps the flattened path of every queried value to its json
+// representation, ex.: "balances/test0/ukuji" -> "\"1000000\""
+type Snapshot map[string]string
+
+func (p *Pond) LoadChecks(filename string) (Checks, error) {
+	var checks Checks
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return checks, p.error(err)
+	}
+
+	err = yaml.Unmarshal(data, &checks)
+	if err != nil {
+		return checks, p.error(err)
+	}
+
+	return checks, nil
+}
+
+func (p *Pond) RunChecks(checks Checks) (Snapshot, error) {
+	p.logger.Info().Msg("run checks")
+
+	snapshot := Snapshot{}
+	node := p.chains[0].Nodes[0]
+
+	for _, module := range checks.Params {
+		args := []string{module, "params", "--output", "json"}
+
+		output, err := node.Query(args)
+		if err != nil {
+			p.logger.Err(err).Str("module", module).Msg(string(output))
+			return nil, err
+		}
+
+		err = snapshot.Add("params/"+module, output)
+		if err != nil {
+			return nil, p.error(err)
+		}
+	}
+
+	for _, name := range checks.Balances {
+		account, found := p.info.Accounts[name]
+		if !found {
+			err := fmt.Errorf("account not found")
+			p.logger.Err(err).Str("account", name).Msg("")
+			return nil, err
+		}
+
+		args := []string{
+			"bank", "balances", account.Addresses["kujira"], "--output", "json",
+		}
+
+		output, err := node.Query(args)
+		if err != nil {
+			p.logger.Err(err).Str("account", name).Msg(string(output))
+			return nil, err
+		}
+
+		var response struct {
+			Balances []struct {
+				Denom  string `json:"denom"`
+				Amount string `json:"amount"`
+			} `json:"balances"`
+		}
+
+		err = json.Unmarshal(output, &response)
+		if err != nil {
+			return nil, p.error(err)
+		}
+
+		// index by denom, so a new denom doesn't shift all others
+		balances := map[string]string{}
+		for _, balance := range response.Balances {
+			balances[balance.Denom] = balance.Amount
+		}
+
+		data, err := json.Marshal(balances)
+		if err != nil {
+			return nil, p.error(err)
+		}
+
+		err = snapshot.Add("balances/"+name, data)
+		if err != nil {
+			return nil, p.error(err)
+		}
+	}
+
+	for _, check := range checks.Contracts {
+		address, err := p.GetContractAddress(check.Contract)
+		if err != nil {
+			return nil, err
+		}
+
+		args := []string{
+			"wasm", "contract-state", "smart", address, check.Query,
+			"--output", "json",
+		}
+
+		output, err := node.Query(args)
+		if err != nil {
+			p.logger.Err(err).Str("contract", check.Contract).Msg(string(output))
+			return nil, err
+		}
+
+		err = snapshot.Add("contracts/"+check.Key(), output)
+		if err != nil {
+			return nil, p.error(err)
+		}
+	}
+
+	return snapshot, nil
+}
+
+// CompareChecks prints all differences between both snapshots and returns
+// an error if any of them isn't covered by the expected paths
+func (p *Pond) CompareChecks(checks Checks, before, after Snapshot) error {
+	keys := map[string]struct{}{}
+	for key := range before {
+		keys[key] = struct{}{}
+	}
+	for key := range after {
+		keys[key] = struct{}{}
+	}
+
+	sorted := []string{}
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	unexpected := 0
+
+	for _, key := range sorted {
+		prev, foundPrev := before[key]
+		next, foundNext := after[key]
+
+		var line string
+
+		switch {
+		case foundPrev && foundNext:
+			if prev == next {
+				continue
+			}
+			line = fmt.Sprintf("~ %s: %s -> %s", key, prev, next)
+		case foundPrev:
+			line = fmt.Sprintf("- %s: %s", key, prev)
+		default:
+			line = fmt.Sprintf("+ %s: %s", key, next)
+		}
+
+		if checks.Expected(key) {
+			line += " (expected)"
+		} else {
+			unexpected += 1
+		}
+
+		fmt.Println(line)
+	}
+
+	if unexpected > 0 {
+		err := fmt.Errorf("%d unexpected differences", unexpected)
+		return p.error(err)
+	}
+
+	p.logger.Info().Msg("no unexpected differences found")
+
+	return nil
+}
+
+func (p *Pond) GetContractAddress(name string) (string, error) {
+	for _, contract := range p.info.Contracts {
+		if contract.Address == name || contract.Label == name {
+			return contract.Address, nil
+		}
+	}
+
+	err := fmt.Errorf("contract not found")
+	p.logger.Err(err).Str("contract", name).Msg("")
+	return "", err
+}
+
+func (c *Checks) Expected(key string) bool {
+	for _, pattern := range c.Expect {
+		if key == pattern || strings.HasPrefix(key, pattern+"/") {
+			return true
+		}
+
+		matched, err := path.Match(pattern, key)
+		if err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *ContractCheck) Key() string {
+	if c.Name != "" {
+		return c.Name
+	}
+
+	var query map[string]json.RawMessage
+	json.Unmarshal([]byte(c.Query), &query)
+
+	keys := []string{}
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return c.Contract + "/" + strings.Join(keys, ",")
+}
+
+func (s Snapshot) Add(prefix string, data []byte) error {
+	var value interface{}
+
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return err
+	}
+
+	return s.flatten(prefix, value)
+}
+
+func (s Snapshot) flatten(prefix string, value interface{}) error {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			err := s.flatten(prefix+"/"+key, item)
+			if err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			err := s.flatten(fmt.Sprintf("%s/%d", prefix, i), item)
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		s[prefix] = string(data)
+	}
+
+	return nil
+}
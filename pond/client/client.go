@@ -0,0 +1,489 @@
+package client
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Client queries a node via its CometBFT RPC and cosmos-sdk REST API
+type Client struct {
+	logger zerolog.Logger
+	http   *http.Client
+	RpcUrl string // ex.: http://127.0.0.1:11157
+	ApiUrl string // ex.: http://127.0.0.1:11117
+}
+
+type Status struct {
+	Height  int64
+	Hash    string
+	AppHash string
+	Time    time.Time
+}
+
+type Block struct {
+	Height  int64
+	Hash    string
+	AppHash string
+	Time    time.Time
+}
+
+type Attribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type Event struct {
+	Type       string      `json:"type"`
+	Attributes []Attribute `json:"attributes"`
+}
+
+type TxResponse struct {
+	Height string  `json:"height"`
+	Hash   string  `json:"txhash"`
+	Code   int     `json:"code"`
+	RawLog string  `json:"raw_log"`
+	Events []Event `json:"events"`
+}
+
+type Code struct {
+	Id       string `json:"code_id"`
+	Checksum string `json:"data_hash"`
+}
+
+type ContractInfo struct {
+	Address string `json:"address"`
+	CodeId  string `json:"code_id"`
+	Creator string `json:"creator"`
+	Admin   string `json:"admin"`
+	Label   string `json:"label"`
+}
+
+// StatusError is returned for all non 200 responses
+type StatusError struct {
+	Url     string
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: %d %s", e.Url, e.Code, e.Message)
+}
+
+func NewClient(logger zerolog.Logger, rpcUrl, apiUrl string) Client {
+	return Client{
+		logger: logger,
+		http:   &http.Client{Timeout: time.Second * 10},
+		RpcUrl: strings.TrimSuffix(rpcUrl, "/"),
+		ApiUrl: strings.TrimSuffix(apiUrl, "/"),
+	}
+}
+
+func (c *Client) get(url string, response any) error {
+	c.logger.Trace().Str("url", url).Msg("http get")
+
+	resp, err := c.http.Get(url)
+	if err != nil {
+		return err
+	}
+
+	return c.decode(url, resp, response)
+}
+
+func (c *Client) post(url string, request, response any) error {
+	c.logger.Trace().Str("url", url).Msg("http post")
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.http.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	return c.decode(url, resp, response)
+}
+
+func (c *Client) decode(url string, resp *http.Response, response any) error {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var msg struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(body, &msg)
+
+		return &StatusError{Url: url, Code: resp.StatusCode, Message: msg.Message}
+	}
+
+	return json.Unmarshal(body, response)
+}
+
+// rpc unwraps the json-rpc result
+func (c *Client) rpc(path string, result any) error {
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+			Data    string `json:"data"`
+		} `json:"error"`
+	}
+
+	err := c.get(c.RpcUrl+path, &response)
+	if err != nil {
+		return err
+	}
+
+	if response.Error != nil {
+		return fmt.Errorf("%s: %s", response.Error.Message, response.Error.Data)
+	}
+
+	return json.Unmarshal(response.Result, result)
+}
+
+func (c *Client) Status() (Status, error) {
+	var result struct {
+		SyncInfo struct {
+			LatestBlockHash   string    `json:"latest_block_hash"`
+			LatestAppHash     string    `json:"latest_app_hash"`
+			LatestBlockHeight string    `json:"latest_block_height"`
+			LatestBlockTime   time.Time `json:"latest_block_time"`
+		} `json:"sync_info"`
+	}
+
+	err := c.rpc("/status", &result)
+	if err != nil {
+		return Status{}, err
+	}
+
+	height, err := strconv.ParseInt(result.SyncInfo.LatestBlockHeight, 10, 64)
+	if err != nil {
+		return Status{}, err
+	}
+
+	return Status{
+		Height:  height,
+		Hash:    result.SyncInfo.LatestBlockHash,
+		AppHash: result.SyncInfo.LatestAppHash,
+		Time:    result.SyncInfo.LatestBlockTime,
+	}, nil
+}
+
+// SdkVersion returns the cosmos-sdk version of the node, ex.: v0.47.5
+func (c *Client) SdkVersion() (string, error) {
+	var response struct {
+		ApplicationVersion struct {
+			CosmosSdkVersion string `json:"cosmos_sdk_version"`
+		} `json:"application_version"`
+	}
+
+	err := c.get(c.ApiUrl+"/cosmos/base/tendermint/v1beta1/node_info", &response)
+	if err != nil {
+		return "", err
+	}
+
+	return response.ApplicationVersion.CosmosSdkVersion, nil
+}
+
+func (c *Client) Block(height int64) (Block, error) {
+	var result struct {
+		BlockId struct {
+			Hash string `json:"hash"`
+		} `json:"block_id"`
+		Block struct {
+			Header struct {
+				Time    time.Time `json:"time"`
+				AppHash string    `json:"app_hash"`
+			} `json:"header"`
+		} `json:"block"`
+	}
+
+	err := c.rpc(fmt.Sprintf("/block?height=%d", height), &result)
+	if err != nil {
+		return Block{}, err
+	}
+
+	return Block{
+		Height:  height,
+		Hash:    result.BlockId.Hash,
+		AppHash: result.Block.Header.AppHash,
+		Time:    result.Block.Header.Time,
+	}, nil
+}
+
+func (c *Client) Tx(hash string) (TxResponse, error) {
+	var response struct {
+		TxResponse TxResponse `json:"tx_response"`
+	}
+
+	err := c.get(c.ApiUrl+"/cosmos/tx/v1beta1/txs/"+hash, &response)
+	if err != nil {
+		return TxResponse{}, err
+	}
+
+	return response.TxResponse, nil
+}
+
+// Codes returns one page of stored codes and the key of the next page
+func (c *Client) Codes(key string) ([]Code, string, error) {
+	var response struct {
+		CodeInfos  []Code `json:"code_infos"`
+		Pagination struct {
+			NextKey string `json:"next_key"`
+		} `json:"pagination"`
+	}
+
+	err := c.get(c.ApiUrl+"/cosmwasm/wasm/v1/code"+page(key), &response)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for i, code := range response.CodeInfos {
+		response.CodeInfos[i].Checksum = checksum(code.Checksum)
+	}
+
+	return response.CodeInfos, response.Pagination.NextKey, nil
+}
+
+// Code returns the wasm byte code of the given code id
+func (c *Client) Code(id string) ([]byte, error) {
+	var response struct {
+		Data string `json:"data"`
+	}
+
+	err := c.get(c.ApiUrl+"/cosmwasm/wasm/v1/code/"+id, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(response.Data)
+}
+
+// ContractsByCreator returns one page of contract addresses and the key of
+// the next page
+func (c *Client) ContractsByCreator(
+	address, key string,
+) ([]string, string, error) {
+	var response struct {
+		Addresses  []string `json:"contract_addresses"`
+		Pagination struct {
+			NextKey string `json:"next_key"`
+		} `json:"pagination"`
+	}
+
+	path := "/cosmwasm/wasm/v1/contracts/creator/" + address + page(key)
+
+	err := c.get(c.ApiUrl+path, &response)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return response.Addresses, response.Pagination.NextKey, nil
+}
+
+func (c *Client) Contract(address string) (ContractInfo, error) {
+	var response struct {
+		Address      string       `json:"address"`
+		ContractInfo ContractInfo `json:"contract_info"`
+	}
+
+	err := c.get(c.ApiUrl+"/cosmwasm/wasm/v1/contract/"+address, &response)
+	if err != nil {
+		return ContractInfo{}, err
+	}
+
+	response.ContractInfo.Address = response.Address
+
+	return response.ContractInfo, nil
+}
+
+// Smart runs a smart query and returns the raw response data
+func (c *Client) Smart(address string, query []byte) (json.RawMessage, error) {
+	var response struct {
+		Data json.RawMessage `json:"data"`
+	}
+
+	path := fmt.Sprintf(
+		"/cosmwasm/wasm/v1/contract/%s/smart/%s",
+		address, base64.URLEncoding.EncodeToString(query),
+	)
+
+	err := c.get(c.ApiUrl+path, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Data, nil
+}
+
+func (c *Client) DenomsFromCreator(address string) ([]string, error) {
+	var response struct {
+		Denoms []string `json:"denoms"`
+	}
+
+	err := c.get(c.ApiUrl+"/kujira/denoms/by_creator/"+address, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Denoms, nil
+}
+
+func page(key string) string {
+	if key == "" {
+		return ""
+	}
+
+	return "?pagination.key=" + url.QueryEscape(key)
+}
+
+// checksum returns the upper case hex representation, some versions
+// encode bytes as base64
+func checksum(value string) string {
+	_, err := hex.DecodeString(value)
+	if err == nil {
+		return strings.ToUpper(value)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return value
+	}
+
+	return strings.ToUpper(hex.EncodeToString(data))
+}
+
+type Coin struct {
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+}
+
+type Channel struct {
+	State        string `json:"state"`
+	ChannelId    string `json:"channel_id"`
+	PortId       string `json:"port_id"`
+	Counterparty struct {
+		ChannelId string `json:"channel_id"`
+		PortId    string `json:"port_id"`
+	} `json:"counterparty"`
+}
+
+func (c *Client) Balances(address string) ([]Coin, error) {
+	var response struct {
+		Balances []Coin `json:"balances"`
+	}
+
+	err := c.get(c.ApiUrl+"/cosmos/bank/v1beta1/balances/"+address, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Balances, nil
+}
+
+func (c *Client) Channels() ([]Channel, error) {
+	var response struct {
+		Channels []Channel `json:"channels"`
+	}
+
+	err := c.get(c.ApiUrl+"/ibc/core/channel/v1/channels", &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Channels, nil
+}
+
+// ChannelChainId returns the chain id of the counterparty of a channel
+func (c *Client) ChannelChainId(port, channel string) (string, error) {
+	var response struct {
+		State struct {
+			ClientState struct {
+				ChainId string `json:"chain_id"`
+			} `json:"client_state"`
+		} `json:"identified_client_state"`
+	}
+
+	url := fmt.Sprintf(
+		"%s/ibc/core/channel/v1/channels/%s/ports/%s/client_state",
+		c.ApiUrl, channel, port,
+	)
+
+	err := c.get(url, &response)
+	if err != nil {
+		return "", err
+	}
+
+	return response.State.ClientState.ChainId, nil
+}
+
+type DenomTrace struct {
+	Path      string `json:"path"`
+	BaseDenom string `json:"base_denom"`
+}
+
+func (c *Client) DenomTraces() ([]DenomTrace, error) {
+	var response struct {
+		DenomTraces []DenomTrace `json:"denom_traces"`
+	}
+
+	err := c.get(c.ApiUrl+"/ibc/apps/transfer/v1/denom_traces", &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.DenomTraces, nil
+}
+
+// Simulate returns the gas used by the base64 encoded tx
+func (c *Client) Simulate(tx string) (string, error) {
+	var response struct {
+		GasInfo struct {
+			GasUsed string `json:"gas_used"`
+		} `json:"gas_info"`
+	}
+
+	err := c.post(
+		c.ApiUrl+"/cosmos/tx/v1beta1/simulate",
+		map[string]string{"tx_bytes": tx}, &response,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return response.GasInfo.GasUsed, nil
+}
+
+// Attribute returns the value of the first matching event attribute
+func (r *TxResponse) Attribute(event, key string) (string, bool) {
+	for _, e := range r.Events {
+		if e.Type != event {
+			continue
+		}
+
+		for _, attribute := range e.Attributes {
+			if attribute.Key == key {
+				return attribute.Value, true
+			}
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,263 @@
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// newTestClient serves the given paths and their json responses, rpc and api
+// share the server
+func newTestClient(t *testing.T, routes map[string]string) Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, found := routes[r.URL.RequestURI()]
+			if !found {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"message":"not found"}`))
+				return
+			}
+
+			w.Write([]byte(body))
+		},
+	))
+
+	t.Cleanup(server.Close)
+
+	return NewClient(zerolog.Nop(), server.URL+"/", server.URL)
+}
+
+func TestStatus(t *testing.T) {
+	c := newTestClient(t, map[string]string{
+		"/status": `{"jsonrpc":"2.0","id":-1,"result":{"sync_info":{
+			"latest_block_hash":"ABC","latest_app_hash":"DEF",
+			"latest_block_height":"42","latest_block_time":"2024-01-02T03:04:05Z"
+		}}}`,
+	})
+
+	status, err := c.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Status{
+		Height:  42,
+		Hash:    "ABC",
+		AppHash: "DEF",
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	if status != expected {
+		t.Errorf("got %+v, want %+v", status, expected)
+	}
+}
+
+func TestStatusRpcError(t *testing.T) {
+	c := newTestClient(t, map[string]string{
+		"/status": `{"error":{"message":"internal error","data":"not ready"}}`,
+	})
+
+	_, err := c.Status()
+	if err == nil || err.Error() != "internal error: not ready" {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestSdkVersion(t *testing.T) {
+	c := newTestClient(t, map[string]string{
+		"/cosmos/base/tendermint/v1beta1/node_info": `{
+			"default_node_info":{"network":"kujira-1"},
+			"application_version":{"name":"kujira","cosmos_sdk_version":"v0.47.5"}
+		}`,
+	})
+
+	version, err := c.SdkVersion()
+	if err != nil || version != "v0.47.5" {
+		t.Errorf("got %q %v", version, err)
+	}
+}
+
+func TestBlock(t *testing.T) {
+	c := newTestClient(t, map[string]string{
+		"/block?height=7": `{"result":{"block_id":{"hash":"H7"},"block":{
+			"header":{"time":"2024-01-02T03:04:05Z","app_hash":"A7"}
+		}}}`,
+	})
+
+	block, err := c.Block(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if block.Height != 7 || block.Hash != "H7" || block.AppHash != "A7" {
+		t.Errorf("got %+v", block)
+	}
+}
+
+func TestTx(t *testing.T) {
+	c := newTestClient(t, map[string]string{
+		"/cosmos/tx/v1beta1/txs/HASH": `{"tx_response":{
+			"height":"5","txhash":"HASH","code":0,"raw_log":"",
+			"events":[{"type":"store_code","attributes":[{"key":"code_id","value":"3"}]}]
+		}}`,
+	})
+
+	tx, err := c.Tx("HASH")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, found := tx.Attribute("store_code", "code_id")
+	if !found || id != "3" || tx.Height != "5" {
+		t.Errorf("got %+v", tx)
+	}
+}
+
+func TestTxNotFound(t *testing.T) {
+	c := newTestClient(t, map[string]string{})
+
+	_, err := c.Tx("MISSING")
+
+	statusErr, ok := err.(*StatusError)
+	if !ok || statusErr.Code != http.StatusNotFound || statusErr.Message != "not found" {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestCodesPagination(t *testing.T) {
+	c := newTestClient(t, map[string]string{
+		"/cosmwasm/wasm/v1/code": `{
+			"code_infos":[{"code_id":"1","data_hash":"abcd"}],
+			"pagination":{"next_key":"a/b+c="}
+		}`,
+		"/cosmwasm/wasm/v1/code?pagination.key=a%2Fb%2Bc%3D": `{
+			"code_infos":[{"code_id":"2","data_hash":"q80="}],
+			"pagination":{"next_key":null}
+		}`,
+	})
+
+	codes := []Code{}
+
+	key := ""
+	for {
+		page, next, err := c.Codes(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		codes = append(codes, page...)
+
+		if next == "" {
+			break
+		}
+
+		key = next
+	}
+
+	expected := []Code{
+		{Id: "1", Checksum: "ABCD"},
+		{Id: "2", Checksum: "ABCD"},
+	}
+
+	if !reflect.DeepEqual(codes, expected) {
+		t.Errorf("got %+v, want %+v", codes, expected)
+	}
+}
+
+func TestContractsByCreator(t *testing.T) {
+	c := newTestClient(t, map[string]string{
+		"/cosmwasm/wasm/v1/contracts/creator/kujira1creator": `{
+			"contract_addresses":["kujira1a"],"pagination":{"next_key":"next"}
+		}`,
+		"/cosmwasm/wasm/v1/contracts/creator/kujira1creator?pagination.key=next": `{
+			"contract_addresses":["kujira1b"],"pagination":{}
+		}`,
+	})
+
+	addresses, next, err := c.ContractsByCreator("kujira1creator", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(addresses, []string{"kujira1a"}) || next != "next" {
+		t.Errorf("got %v %q", addresses, next)
+	}
+
+	addresses, next, err = c.ContractsByCreator("kujira1creator", next)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(addresses, []string{"kujira1b"}) || next != "" {
+		t.Errorf("got %v %q", addresses, next)
+	}
+}
+
+func TestSmart(t *testing.T) {
+	query := []byte(`{"config":{}}`)
+	path := "/cosmwasm/wasm/v1/contract/kujira1contract/smart/" +
+		base64.URLEncoding.EncodeToString(query)
+
+	c := newTestClient(t, map[string]string{
+		path: `{"data":{"owner":"kujira1owner"}}`,
+	})
+
+	data, err := c.Smart("kujira1contract", query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config struct {
+		Owner string `json:"owner"`
+	}
+
+	err = json.Unmarshal(data, &config)
+	if err != nil || config.Owner != "kujira1owner" {
+		t.Errorf("got %s", data)
+	}
+}
+
+func TestDenomsFromCreator(t *testing.T) {
+	c := newTestClient(t, map[string]string{
+		"/kujira/denoms/by_creator/kujira1creator": `{
+			"denoms":["factory/kujira1creator/upond"]
+		}`,
+	})
+
+	denoms, err := c.DenomsFromCreator("kujira1creator")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(denoms, []string{"factory/kujira1creator/upond"}) {
+		t.Errorf("got %v", denoms)
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"abcdef01", "ABCDEF01"},
+		{"ABCDEF01", "ABCDEF01"},
+		{"q83vAQ==", "ABCDEF01"},
+		{"not a checksum!", "not a checksum!"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		result := checksum(test.value)
+		if result != test.expected {
+			t.Errorf("checksum(%q) = %q, want %q", test.value, result, test.expected)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type Tally struct {
+	Yes        string `json:"yes_count"`
+	Abstain    string `json:"abstain_count"`
+	No         string `json:"no_count"`
+	NoWithVeto string `json:"no_with_veto_count"`
+}
+
+type Proposal struct {
+	Id               string            `json:"id"`
+	Title            string            `json:"title"`
+	Summary          string            `json:"summary"`
+	Status           string            `json:"status"` // ex.: PROPOSAL_STATUS_VOTING_PERIOD
+	Messages         []json.RawMessage `json:"messages"`
+	FinalTallyResult Tally             `json:"final_tally_result"`
+	TotalDeposit     []Coin            `json:"total_deposit"`
+	SubmitTime       *time.Time        `json:"submit_time"`
+	DepositEndTime   *time.Time        `json:"deposit_end_time"`
+	VotingStartTime  *time.Time        `json:"voting_start_time"`
+	VotingEndTime    *time.Time        `json:"voting_end_time"`
+	FailedReason     string            `json:"failed_reason,omitempty"` // sdk-50
+}
+
+// Proposals returns all proposals, optionally filtered by status,
+// ex.: PROPOSAL_STATUS_VOTING_PERIOD
+func (c *Client) Proposals(status string) ([]Proposal, error) {
+	path := "/cosmos/gov/v1/proposals?pagination.limit=1000"
+	if status != "" {
+		path += "&proposal_status=" + status
+	}
+
+	var response struct {
+		Proposals []Proposal `json:"proposals"`
+	}
+
+	err := c.get(c.ApiUrl+path, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Proposals, nil
+}
+
+func (c *Client) Proposal(id string) (Proposal, error) {
+	var response struct {
+		Proposal Proposal `json:"proposal"`
+	}
+
+	err := c.get(c.ApiUrl+"/cosmos/gov/v1/proposals/"+id, &response)
+	if err != nil {
+		return Proposal{}, err
+	}
+
+	return response.Proposal, nil
+}
+
+// Tally returns the current tally of a proposal in voting period
+func (c *Client) Tally(id string) (Tally, error) {
+	var response struct {
+		Tally Tally `json:"tally"`
+	}
+
+	err := c.get(c.ApiUrl+"/cosmos/gov/v1/proposals/"+id+"/tally", &response)
+	if err != nil {
+		return Tally{}, err
+	}
+
+	return response.Tally, nil
+}
+
+// MinDeposit returns the deposit needed to enter the voting period
+func (c *Client) MinDeposit() ([]Coin, error) {
+	type Params struct {
+		MinDeposit []Coin `json:"min_deposit"`
+	}
+
+	var response struct {
+		Params        Params `json:"params"`
+		DepositParams Params `json:"deposit_params"`
+	}
+
+	err := c.get(c.ApiUrl+"/cosmos/gov/v1/params/deposit", &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(response.Params.MinDeposit) > 0 {
+		return response.Params.MinDeposit, nil
+	}
+
+	return response.DepositParams.MinDeposit, nil
+}
+
+// BlockEvents returns the events emitted outside of txs
+func (c *Client) BlockEvents(height int64) ([]Event, error) {
+	var result struct {
+		// cometbft 0.37
+		BeginBlockEvents []Event `json:"begin_block_events"`
+		EndBlockEvents   []Event `json:"end_block_events"`
+		// cometbft 0.38
+		FinalizeBlockEvents []Event `json:"finalize_block_events"`
+	}
+
+	err := c.rpc(fmt.Sprintf("/block_results?height=%d", height), &result)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []Event{}
+	events = append(events, result.BeginBlockEvents...)
+	events = append(events, result.EndBlockEvents...)
+	events = append(events, result.FinalizeBlockEvents...)
+
+	return events, nil
+}
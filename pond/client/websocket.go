@@ -0,0 +1,214 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	QueryBlocks = "tm.event='NewBlock'"
+	QueryTxs    = "tm.event='Tx'"
+)
+
+// Message is either a new block or a tx, depending on its type
+type Message struct {
+	Type   string    `json:"type"` // ex.: NewBlock or Tx
+	Height int64     `json:"height"`
+	Time   time.Time `json:"time,omitempty"` // blocks only
+	Txs    int       `json:"txs,omitempty"`  // blocks only
+	Hash   string    `json:"hash,omitempty"` // txs only
+	Code   int       `json:"code"`
+	Log    string    `json:"log,omitempty"`
+	Events []Event   `json:"events"`
+}
+
+// Subscribe streams all messages matching the queries to the handler until
+// the context is done or the connection fails
+func (c *Client) Subscribe(
+	ctx context.Context, handler func(Message) error, queries ...string,
+) error {
+	url := strings.Replace(c.RpcUrl, "http", "ws", 1) + "/websocket"
+
+	c.logger.Debug().Str("url", url).Msg("connect websocket")
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	for i, query := range queries {
+		err = conn.WriteJSON(map[string]any{
+			"jsonrpc": "2.0",
+			"method":  "subscribe",
+			"id":      i,
+			"params":  map[string]string{"query": query},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// unblock ReadMessage once the context is done
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		msg, found, err := parseMessage(data)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			continue
+		}
+
+		err = handler(msg)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func parseMessage(data []byte) (Message, bool, error) {
+	var response struct {
+		Result struct {
+			Data struct {
+				Type  string          `json:"type"`
+				Value json.RawMessage `json:"value"`
+			} `json:"data"`
+			Events map[string][]string `json:"events"`
+		} `json:"result"`
+		Error *struct {
+			Message string `json:"message"`
+			Data    string `json:"data"`
+		} `json:"error"`
+	}
+
+	err := json.Unmarshal(data, &response)
+	if err != nil {
+		return Message{}, false, err
+	}
+
+	if response.Error != nil {
+		err := fmt.Errorf("%s: %s", response.Error.Message, response.Error.Data)
+		return Message{}, false, err
+	}
+
+	value := response.Result.Data.Value
+
+	switch response.Result.Data.Type {
+	case "tendermint/event/NewBlock":
+		msg, err := parseBlock(value)
+		return msg, true, err
+	case "tendermint/event/Tx":
+		msg, err := parseTx(value)
+		if err != nil {
+			return msg, true, err
+		}
+
+		hashes := response.Result.Events["tx.hash"]
+		if len(hashes) > 0 {
+			msg.Hash = hashes[0]
+		}
+
+		return msg, true, nil
+	}
+
+	// subscription confirmations have an empty result
+	return Message{}, false, nil
+}
+
+func parseBlock(data []byte) (Message, error) {
+	type Result struct {
+		Events []Event `json:"events"`
+	}
+
+	var value struct {
+		Block struct {
+			Header struct {
+				Height string    `json:"height"`
+				Time   time.Time `json:"time"`
+			} `json:"header"`
+			Data struct {
+				Txs []string `json:"txs"`
+			} `json:"data"`
+		} `json:"block"`
+		// cometbft 0.37
+		BeginBlock Result `json:"result_begin_block"`
+		EndBlock   Result `json:"result_end_block"`
+		// cometbft 0.38
+		FinalizeBlock Result `json:"result_finalize_block"`
+	}
+
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return Message{}, err
+	}
+
+	height, err := strconv.ParseInt(value.Block.Header.Height, 10, 64)
+	if err != nil {
+		return Message{}, err
+	}
+
+	events := []Event{}
+	events = append(events, value.BeginBlock.Events...)
+	events = append(events, value.EndBlock.Events...)
+	events = append(events, value.FinalizeBlock.Events...)
+
+	return Message{
+		Type:   "NewBlock",
+		Height: height,
+		Time:   value.Block.Header.Time,
+		Txs:    len(value.Block.Data.Txs),
+		Events: events,
+	}, nil
+}
+
+func parseTx(data []byte) (Message, error) {
+	var value struct {
+		TxResult struct {
+			Height string `json:"height"`
+			Result struct {
+				Code   int     `json:"code"`
+				Log    string  `json:"log"`
+				Events []Event `json:"events"`
+			} `json:"result"`
+		} `json:"TxResult"`
+	}
+
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return Message{}, err
+	}
+
+	height, err := strconv.ParseInt(value.TxResult.Height, 10, 64)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		Type:   "Tx",
+		Height: height,
+		Code:   value.TxResult.Result.Code,
+		Log:    value.TxResult.Result.Log,
+		Events: value.TxResult.Result.Events,
+	}, nil
+}
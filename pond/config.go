@@ -18,6 +18,9 @@ type Config struct {
 	RpcUrl    string            `json:"rpc_url"`
 	Address   string            `json:"address"`
 	Binary    string            `json:"binary"`
+	Exports   []Export          `json:"exports"` // regenerated after deployments
+	Sources   map[string]string `json:"sources"` // code source schemes
+	Offline   bool              `json:"offline"` // cached codes and static prices only
 }
 
 func (p *Pond) LoadConfig() error {
@@ -0,0 +1,236 @@
+package pond
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"pond/pond/deployer"
+	"pond/utils"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// changes within this delay are deployed together, ex.: optimizer builds
+const watchDelay = time.Millisecond * 500
+
+// DeployWatch deploys the files and deploys them again on every change.
+// Changed wasm files are stored and the plan files deployed again, which
+// migrates all contracts using the new codes.
+func (p *Pond) DeployWatch(
+	filenames []string, from string, vars map[string]string,
+) error {
+	err := p.Deploy(filenames, from, vars, false)
+	if err != nil {
+		p.logger.Warn().Msg("deployment failed, waiting for changes")
+	}
+
+	files, err := deployer.ExpandFiles(p.logger, filenames)
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return p.error(err)
+	}
+
+	defer watcher.Close()
+
+	// watch directories, editors and builds often replace files
+	watched := map[string]string{}
+	checksums := map[string]string{}
+	dirs := map[string]struct{}{}
+
+	for _, file := range files {
+		path, err := filepath.Abs(file)
+		if err != nil {
+			return p.error(err)
+		}
+
+		watched[path] = file
+
+		data, err := os.ReadFile(file)
+		if err == nil {
+			checksums[path] = utils.Sha256(data)
+		}
+
+		dir := filepath.Dir(path)
+		_, found := dirs[dir]
+		if found {
+			continue
+		}
+
+		err = watcher.Add(dir)
+		if err != nil {
+			return p.error(err)
+		}
+
+		dirs[dir] = struct{}{}
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	timer := time.NewTimer(watchDelay)
+	timer.Stop()
+
+	changed := map[string]struct{}{}
+
+	p.logger.Info().Int("files", len(files)).Msg("watch files")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			_, found := watched[event.Name]
+			if !found || !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+				continue
+			}
+
+			changed[event.Name] = struct{}{}
+			timer.Reset(watchDelay)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			p.logger.Warn().Err(err).Msg("watch files")
+
+		case <-timer.C:
+			p.redeploy(files, watched, changed, checksums)
+			changed = map[string]struct{}{}
+		}
+	}
+}
+
+// redeploy stores the changed wasm files and deploys all plan files again
+func (p *Pond) redeploy(
+	files []string,
+	watched map[string]string,
+	changed map[string]struct{},
+	checksums map[string]string,
+) {
+	redeploy := false
+
+	for path := range changed {
+		file := watched[path]
+
+		fileType, err := p.deployer.FileType(file)
+		if err != nil {
+			continue
+		}
+
+		if fileType == "plan" {
+			p.logger.Info().Str("file", file).Msg("plan changed")
+			redeploy = true
+			continue
+		}
+
+		if fileType != "wasm" {
+			continue
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		checksum := utils.Sha256(data)
+		if checksum == checksums[path] {
+			continue
+		}
+
+		checksums[path] = checksum
+		redeploy = true
+
+		name := p.deployer.CodeName(file)
+		p.logger.Info().Str("name", name).Msg("code changed")
+
+		err = p.deployer.DeployWasm(name, file, data)
+		if err != nil {
+			return
+		}
+
+		fmt.Printf("code %s: %s\n", name, p.deployer.CodeIds[name])
+	}
+
+	if !redeploy {
+		return
+	}
+
+	before := map[string]Contract{}
+	for _, contract := range p.info.Contracts {
+		before[contract.Address] = contract
+	}
+
+	p.deployer.ResetPlan()
+
+	plans := 0
+	for _, file := range files {
+		fileType, err := p.deployer.FileType(file)
+		if err != nil || fileType != "plan" {
+			continue
+		}
+
+		err = p.deployer.LoadPlanFile(file)
+		if err != nil {
+			return
+		}
+
+		plans++
+	}
+
+	if plans > 0 {
+		err := p.deployer.DeployPlan()
+		if err != nil {
+			return
+		}
+	}
+
+	err := p.UpdateCodes()
+	if err != nil {
+		return
+	}
+
+	err = p.UpdateContracts()
+	if err != nil {
+		return
+	}
+
+	p.WriteExports()
+
+	contracts := []Contract{}
+	for _, contract := range p.info.Contracts {
+		previous, found := before[contract.Address]
+		if !found || previous.CodeId != contract.CodeId {
+			contracts = append(contracts, contract)
+		}
+	}
+
+	sort.Slice(contracts, func(i, j int) bool {
+		return contracts[i].Label < contracts[j].Label
+	})
+
+	for _, contract := range contracts {
+		name := contract.Name
+		if name == "" {
+			name = contract.Label
+		}
+
+		fmt.Printf(
+			"contract %s: %s (code %s)\n", name, contract.Address, contract.CodeId,
+		)
+	}
+}
@@ -0,0 +1,247 @@
+package deployer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"pond/pond/chain/node"
+	"pond/pond/client"
+)
+
+// target holds the deployer state of a chain that is not in use
+type target struct {
+	node      node.Node
+	prefix    string
+	address   string
+	codes     map[string]string
+	codeIds   map[string]string
+	addresses map[string]struct{}
+	labels    map[string]client.ContractInfo
+}
+
+func (s *Stage) UnmarshalJSON(data []byte) error {
+	var contracts []Contract
+
+	err := json.Unmarshal(data, &contracts)
+	if err == nil {
+		*s = contracts
+		return nil
+	}
+
+	var stage struct {
+		Chain     string      `json:"chain"`
+		Contracts *[]Contract `json:"contracts"`
+	}
+
+	// contracts of flat lists are no stages
+	if json.Unmarshal(data, &stage) != nil || stage.Contracts == nil {
+		return err
+	}
+
+	setChain(*stage.Contracts, stage.Chain)
+	*s = *stage.Contracts
+
+	return nil
+}
+
+// AddChain adds another chain running cosmwasm, plans can deploy contracts
+// to it by its chain id
+func (d *Deployer) AddChain(node node.Node, prefix string) {
+	d.chains[node.ChainId] = &target{
+		node:      node,
+		prefix:    prefix,
+		address:   d.remotes[node.ChainId][d.from],
+		codes:     map[string]string{},
+		codeIds:   map[string]string{},
+		addresses: map[string]struct{}{},
+		labels:    map[string]client.ContractInfo{},
+	}
+}
+
+// UseChain switches all txs and queries to the node of the given chain,
+// empty uses the default chain
+func (d *Deployer) UseChain(chainId string) error {
+	if chainId == "" {
+		chainId = d.defaultChain
+	}
+
+	if chainId == d.chainId {
+		return nil
+	}
+
+	next, found := d.chains[chainId]
+	if !found {
+		err := fmt.Errorf("chain not found or without cosmwasm")
+		d.logger.Err(err).Str("chain", chainId).Msg("")
+		return err
+	}
+
+	d.logger.Debug().Str("chain", chainId).Msg("use chain")
+
+	d.chains[d.chainId] = &target{
+		node:      d.node,
+		prefix:    d.prefix,
+		address:   d.address,
+		codes:     d.codes,
+		codeIds:   d.CodeIds,
+		addresses: d.addresses,
+		labels:    d.labels,
+	}
+
+	delete(d.chains, chainId)
+
+	d.chainId = chainId
+	d.node = next.node
+	d.prefix = next.prefix
+	d.address = next.address
+	d.codes = next.codes
+	d.CodeIds = next.codeIds
+	d.addresses = next.addresses
+	d.labels = next.labels
+
+	return nil
+}
+
+// getChainAccounts returns the account addresses on the given chain
+func (d *Deployer) getChainAccounts(chainId string) map[string]string {
+	if chainId == d.defaultChain {
+		return d.accounts
+	}
+
+	return d.remotes[chainId]
+}
+
+// getAccount returns the address of an account on the current chain
+func (d *Deployer) getAccount(name string) (string, bool) {
+	address, found := d.getChainAccounts(d.chainId)[name]
+	return address, found
+}
+
+// isAddress checks the prefix of addresses on the current chain
+func (d *Deployer) isAddress(value string) bool {
+	return strings.HasPrefix(value, d.prefix+"1")
+}
+
+// getPlanChains returns the chains of the plan contracts, the default
+// chain first
+func (d *Deployer) getPlanChains() []string {
+	chains := []string{d.defaultChain}
+	found := map[string]struct{}{d.defaultChain: {}}
+
+	for _, contracts := range d.plan.Contracts {
+		for _, contract := range contracts {
+			chainId := d.getContractChain(contract)
+
+			_, exists := found[chainId]
+			if exists {
+				continue
+			}
+
+			found[chainId] = struct{}{}
+			chains = append(chains, chainId)
+		}
+	}
+
+	return chains
+}
+
+func (d *Deployer) getContractChain(contract Contract) string {
+	if contract.Chain == "" {
+		return d.defaultChain
+	}
+
+	return contract.Chain
+}
+
+// groupByChain splits the contracts of a stage by their chains, in order of
+// their first contract
+func (d *Deployer) groupByChain(contracts []Contract) ([]string, map[string][]Contract) {
+	chains := []string{}
+	groups := map[string][]Contract{}
+
+	for _, contract := range contracts {
+		chainId := d.getContractChain(contract)
+
+		_, found := groups[chainId]
+		if !found {
+			chains = append(chains, chainId)
+		}
+
+		groups[chainId] = append(groups[chainId], contract)
+	}
+
+	return chains, groups
+}
+
+// setChain sets the chain of all contracts without their own chain
+func setChain(contracts []Contract, chainId string) {
+	for i := range contracts {
+		if contracts[i].Chain == "" {
+			contracts[i].Chain = chainId
+		}
+	}
+}
+
+// deployChainCodes stores the missing codes of the plan contracts on
+// another chain
+func (d *Deployer) deployChainCodes(chainId string) error {
+	err := d.UseChain(chainId)
+	if err != nil {
+		return err
+	}
+
+	err = d.UpdateDeployedCodes()
+	if err != nil {
+		return err
+	}
+
+	codes, err := d.GetMissingCodes()
+	if err != nil {
+		return err
+	}
+
+	err = d.UpdateDeployedContracts()
+	if err != nil {
+		return err
+	}
+
+	msgs, err := d.CreateCodeMsgs(codes)
+	if err != nil {
+		return err
+	}
+
+	if d.DryRun {
+		fmt.Printf("\nchain %s\n", chainId)
+		d.PrintCodes(codes)
+		d.simulate(msgs)
+		return nil
+	}
+
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	d.logger.Info().Str("chain", chainId).Msg("deploy codes")
+
+	err = d.SignAndSend(msgs)
+	if err != nil {
+		return err
+	}
+
+	return d.UpdateDeployedCodes()
+}
+
+// getChainCodes returns the deployed codes of a chain by checksum
+func (d *Deployer) getChainCodes(chainId string) map[string]string {
+	if chainId == d.chainId {
+		return d.codes
+	}
+
+	target, found := d.chains[chainId]
+	if !found {
+		return map[string]string{}
+	}
+
+	return target.codes
+}
@@ -0,0 +1,321 @@
+package deployer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"pond/pond/registry"
+	"pond/utils"
+
+	"github.com/rs/zerolog"
+)
+
+var testCode = []byte("\x00asm test code")
+
+func newCodeDeployer(t *testing.T, sources map[string]string) *Deployer {
+	t.Helper()
+
+	return &Deployer{
+		logger:       zerolog.Nop(),
+		home:         t.TempDir(),
+		sources:      sources,
+		codes:        map[string]string{},
+		chainId:      "kujira-1",
+		defaultChain: "kujira-1",
+	}
+}
+
+// newCodeServer serves the given paths and counts the requests
+func newCodeServer(t *testing.T, routes map[string][]byte) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+
+			body, found := routes[r.URL.Path]
+			if !found {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			w.Write(body)
+		},
+	))
+
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestGetCodeFile(t *testing.T) {
+	d := newCodeDeployer(t, nil)
+
+	filename := filepath.Join(t.TempDir(), "test.wasm")
+
+	err := os.WriteFile(filename, testCode, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := d.GetCode(registry.Code{
+		Source: "file://" + filename, Checksum: utils.Sha256(testCode),
+	})
+	if err != nil || string(data) != string(testCode) {
+		t.Errorf("got %q %v", data, err)
+	}
+
+	_, err = d.GetCode(registry.Code{
+		Source: "file://" + filename, Checksum: utils.Sha256([]byte("other")),
+	})
+	if err == nil || err.Error() != "checksum mismatch" {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestGetCodeCache(t *testing.T) {
+	server, requests := newCodeServer(t, map[string][]byte{"/test.wasm": testCode})
+
+	d := newCodeDeployer(t, nil)
+
+	code := registry.Code{
+		Source: server.URL + "/test.wasm", Checksum: utils.Sha256(testCode),
+	}
+
+	for i := 0; i < 2; i++ {
+		data, err := d.GetCode(code)
+		if err != nil || string(data) != string(testCode) {
+			t.Fatalf("got %q %v", data, err)
+		}
+	}
+
+	if *requests != 1 {
+		t.Errorf("got %d requests, want 1", *requests)
+	}
+
+	// cached codes are available offline
+	d.Offline = true
+
+	_, err := d.GetCode(code)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGetCodeBrokenCache(t *testing.T) {
+	server, requests := newCodeServer(t, map[string][]byte{"/test.wasm": testCode})
+
+	d := newCodeDeployer(t, nil)
+
+	checksum := utils.Sha256(testCode)
+	dir := filepath.Join(d.home, "cache", "codes")
+
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, checksum), []byte("broken"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := d.GetCode(registry.Code{
+		Source: server.URL + "/test.wasm", Checksum: checksum,
+	})
+	if err != nil || string(data) != string(testCode) || *requests != 1 {
+		t.Errorf("got %q %v after %d requests", data, err, *requests)
+	}
+}
+
+func TestGetCodeChecksumMismatch(t *testing.T) {
+	server, _ := newCodeServer(t, map[string][]byte{"/test.wasm": []byte("other")})
+
+	d := newCodeDeployer(t, nil)
+
+	checksum := utils.Sha256(testCode)
+
+	_, err := d.GetCode(registry.Code{
+		Source: server.URL + "/test.wasm", Checksum: checksum,
+	})
+	if err == nil || err.Error() != "checksum mismatch" {
+		t.Errorf("got %v", err)
+	}
+
+	// rejected codes aren't cached
+	_, found := d.getCachedCode(checksum)
+	if found {
+		t.Error("rejected code cached")
+	}
+
+	_, err = os.Stat(filepath.Join(d.home, "cache", "codes", utils.Sha256([]byte("other"))))
+	if err == nil {
+		t.Error("rejected code cached")
+	}
+}
+
+func TestGetCodeErrors(t *testing.T) {
+	d := newCodeDeployer(t, map[string]string{})
+
+	tests := []struct {
+		code     registry.Code
+		offline  bool
+		expected string
+	}{
+		{registry.Code{Source: "https://example.com/test.wasm"}, false, "checksum required"},
+		{registry.Code{Source: "unknown-1://5", Checksum: "AB"}, false, "scheme not supported"},
+		{
+			registry.Code{Source: "https://example.com/test.wasm", Checksum: "AB"},
+			true, "code not cached, run pond registry fetch",
+		},
+	}
+
+	for _, test := range tests {
+		d.Offline = test.offline
+
+		_, err := d.GetCode(test.code)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("%s: got %v, want %s", test.code.Source, err, test.expected)
+		}
+	}
+}
+
+func TestGetCodeHttps(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write(testCode)
+		},
+	))
+	defer server.Close()
+
+	// downloads use the default client
+	client := http.DefaultClient
+	http.DefaultClient = server.Client()
+	defer func() { http.DefaultClient = client }()
+
+	d := newCodeDeployer(t, nil)
+
+	if !strings.HasPrefix(server.URL, "https://") {
+		t.Fatalf("got %s", server.URL)
+	}
+
+	data, err := d.GetCode(registry.Code{
+		Source: server.URL + "/test.wasm", Checksum: utils.Sha256(testCode),
+	})
+	if err != nil || string(data) != string(testCode) {
+		t.Errorf("got %q %v", data, err)
+	}
+}
+
+func TestGetCodeChainSource(t *testing.T) {
+	response, err := json.Marshal(map[string]any{
+		"code_info": map[string]string{"data_hash": utils.Sha256(testCode)},
+		"data":      base64.StdEncoding.EncodeToString(testCode),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, _ := newCodeServer(t, map[string][]byte{
+		"/cosmwasm/wasm/v1/code/5": response,
+	})
+
+	d := newCodeDeployer(t, map[string]string{"kaiyo-1": server.URL + "/"})
+
+	data, err := d.GetCode(registry.Code{
+		Source: "kaiyo-1://5", Checksum: utils.Sha256(testCode),
+	})
+	if err != nil || string(data) != string(testCode) {
+		t.Errorf("got %q %v", data, err)
+	}
+}
+
+func TestGetMissingCodesError(t *testing.T) {
+	server, _ := newCodeServer(t, map[string][]byte{"/good.wasm": testCode})
+
+	d := newCodeDeployer(t, nil)
+
+	filename := filepath.Join(t.TempDir(), "registry.json")
+
+	data, err := json.Marshal(map[string]registry.Code{
+		"good": {Source: server.URL + "/good.wasm", Checksum: utils.Sha256(testCode)},
+		"bad":  {Source: server.URL + "/bad.wasm", Checksum: utils.Sha256(testCode)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filename, data, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.registry, err = registry.NewRegistry(zerolog.Nop(), filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.plan.Contracts = [][]Contract{{{Name: "a", Code: "good"}, {Name: "b", Code: "bad"}}}
+
+	// the missing code is a 404 with an empty body
+	_, err = d.GetMissingCodes()
+	if err == nil || err.Error() != "failed loading code" {
+		t.Errorf("got %v, want failed loading code", err)
+	}
+}
+
+func TestDeployWasmSource(t *testing.T) {
+	d := newCodeDeployer(t, nil)
+	d.codes[utils.Sha256(testCode)] = "1"
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "registry.json")
+
+	err := os.WriteFile(filename, []byte("{}"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.registry, err = registry.NewRegistry(zerolog.Nop(), filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(cwd)
+
+	err = os.Chdir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// temp dirs can be symlinks
+	dir, err = os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// already deployed, only registered
+	err = d.DeployWasm("test", "artifacts/test.wasm", testCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := d.registry.Codes()["test"].Source
+	expected := "file://" + filepath.Join(dir, "artifacts", "test.wasm")
+	if source != expected {
+		t.Errorf("got %s, want %s", source, expected)
+	}
+}
@@ -0,0 +1,361 @@
+package deployer
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"pond/pond/client"
+)
+
+// timeout of ibc transfers, relative to their creation
+const transferTimeout = time.Minute * 10
+
+func (d *Deployer) denomPath(denom Denom) string {
+	return fmt.Sprintf("factory/%s/%s", d.address, denom.Nonce)
+}
+
+func (d *Deployer) createMintMsg(
+	denom Denom, amount, recipient string,
+) (json.RawMessage, error) {
+	data, err := json.Marshal(MintMsg{
+		Type:   "/kujira.denom.MsgMint",
+		Sender: d.address,
+		Amount: Funds{
+			Amount: strings.Replace(amount, "_", "", -1),
+			Denom:  d.denomPath(denom),
+		},
+		Recipient: recipient,
+	})
+	if err != nil {
+		return nil, d.error(err)
+	}
+
+	return data, nil
+}
+
+// createDenomSetupMsgs returns the metadata, mints to accounts and addresses
+// and ibc transfers of a new denom. Mints to plan contracts are created
+// after their instantiation.
+func (d *Deployer) createDenomSetupMsgs(denom Denom) ([]json.RawMessage, error) {
+	msgs := []json.RawMessage{}
+
+	if denom.Metadata != nil {
+		data, err := json.Marshal(MetadataMsg{
+			Type:     "/kujira.denom.MsgSetDenomMetadata",
+			Sender:   d.address,
+			Metadata: d.bankMetadata(denom),
+		})
+		if err != nil {
+			return nil, d.error(err)
+		}
+
+		msgs = append(msgs, data)
+	}
+
+	for _, name := range sortedNames(denom.Recipients) {
+		if d.isPlanContract(name) {
+			// checked before anything is deployed, minted after instantiation
+			err := d.checkRecipientChain(name)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		address, err := d.getRecipient(name)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := d.createMintMsg(denom, denom.Recipients[name], address)
+		if err != nil {
+			return nil, err
+		}
+
+		msgs = append(msgs, data)
+	}
+
+	for _, transfer := range denom.Transfers {
+		channel, err := d.getChannel(transfer.Chain)
+		if err != nil {
+			return nil, err
+		}
+
+		receiver, err := d.getRemoteRecipient(transfer)
+		if err != nil {
+			return nil, err
+		}
+
+		// the transferred amount is minted to the sender first
+		data, err := d.createMintMsg(denom, transfer.Amount, d.address)
+		if err != nil {
+			return nil, err
+		}
+
+		msgs = append(msgs, data)
+
+		timeout := time.Now().Add(transferTimeout).UnixNano()
+
+		data, err = json.Marshal(TransferMsg{
+			Type:          "/ibc.applications.transfer.v1.MsgTransfer",
+			SourcePort:    channel.PortId,
+			SourceChannel: channel.ChannelId,
+			Token: Funds{
+				Amount: strings.Replace(transfer.Amount, "_", "", -1),
+				Denom:  d.denomPath(denom),
+			},
+			Sender:           d.address,
+			Receiver:         receiver,
+			TimeoutTimestamp: fmt.Sprint(timeout),
+		})
+		if err != nil {
+			return nil, d.error(err)
+		}
+
+		msgs = append(msgs, data)
+	}
+
+	return msgs, nil
+}
+
+// CreateDistributionMsgs mints new denoms to their plan contract recipients,
+// after all contracts are instantiated
+func (d *Deployer) CreateDistributionMsgs(denoms []Denom) ([]json.RawMessage, error) {
+	msgs := []json.RawMessage{}
+
+	for _, denom := range denoms {
+		for _, name := range sortedNames(denom.Recipients) {
+			if !d.isPlanContract(name) {
+				continue
+			}
+
+			contract, found := d.Contracts[name]
+			if !found {
+				err := fmt.Errorf("contract not found")
+				d.logger.Err(err).Str("recipient", name).Msg("")
+				return nil, err
+			}
+
+			err := d.checkRecipientChain(name)
+			if err != nil {
+				return nil, err
+			}
+
+			data, err := d.createMintMsg(
+				denom, denom.Recipients[name], contract.Address,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			msgs = append(msgs, data)
+		}
+	}
+
+	return msgs, nil
+}
+
+// SetIbcPaths stores the ibc denoms of a denom on all chains it gets
+// transferred to
+func (d *Deployer) SetIbcPaths(denom Denom) error {
+	current, found := d.Denoms[denom.Name]
+	if !found || len(denom.Transfers) == 0 {
+		return nil
+	}
+
+	current.IbcPath = map[string]string{}
+
+	for _, transfer := range denom.Transfers {
+		channel, err := d.getChannel(transfer.Chain)
+		if err != nil {
+			return err
+		}
+
+		// denoms are prefixed with the port and channel of the receiver
+		trace := fmt.Sprintf(
+			"%s/%s/%s", channel.Counterparty.PortId,
+			channel.Counterparty.ChannelId, current.Path,
+		)
+
+		current.IbcPath[transfer.Chain] = fmt.Sprintf(
+			"ibc/%X", sha256.Sum256([]byte(trace)),
+		)
+	}
+
+	d.Denoms[denom.Name] = current
+
+	return nil
+}
+
+func (d *Deployer) bankMetadata(denom Denom) BankMetadata {
+	base := d.denomPath(denom)
+	metadata := denom.Metadata
+
+	name := metadata.Name
+	if name == "" {
+		name = denom.Name
+	}
+
+	symbol := metadata.Symbol
+	if symbol == "" {
+		symbol = denom.Name
+	}
+
+	units := []DenomUnit{{Denom: base, Exponent: 0, Aliases: []string{}}}
+	display := base
+
+	// exponents must increase, so a display unit needs one
+	if metadata.Exponent > 0 {
+		display = metadata.Display
+		if display == "" {
+			display = strings.ToLower(symbol)
+		}
+
+		units = append(units, DenomUnit{
+			Denom: display, Exponent: metadata.Exponent, Aliases: []string{},
+		})
+	}
+
+	return BankMetadata{
+		Description: metadata.Description,
+		DenomUnits:  units,
+		Base:        base,
+		Display:     display,
+		Name:        name,
+		Symbol:      symbol,
+	}
+}
+
+// getChannel returns the open transfer channel from the chain in use to the
+// given chain
+func (d *Deployer) getChannel(chainId string) (client.Channel, error) {
+	key := d.chainId + "/" + chainId
+
+	channel, found := d.channels[key]
+	if found {
+		return channel, nil
+	}
+
+	c := d.node.Client()
+
+	channels, err := c.Channels()
+	if err != nil {
+		return client.Channel{}, d.error(err)
+	}
+
+	for _, channel := range channels {
+		if channel.PortId != "transfer" || channel.State != "STATE_OPEN" {
+			continue
+		}
+
+		counterparty, err := c.ChannelChainId(channel.PortId, channel.ChannelId)
+		if err != nil {
+			return client.Channel{}, d.error(err)
+		}
+
+		if counterparty == chainId {
+			d.channels[key] = channel
+			return channel, nil
+		}
+	}
+
+	err = fmt.Errorf("transfer channel not found")
+	d.logger.Err(err).Str("chain", chainId).Msg("")
+	return client.Channel{}, err
+}
+
+// getRecipient resolves account names, addresses are used as they are
+func (d *Deployer) getRecipient(name string) (string, error) {
+	address, found := d.getAccount(name)
+	if found {
+		return address, nil
+	}
+
+	if d.isAddress(name) {
+		return name, nil
+	}
+
+	err := fmt.Errorf("recipient not found")
+	d.logger.Err(err).Str("recipient", name).Msg("")
+	return "", err
+}
+
+// getRemoteRecipient resolves the account name of a transfer on its chain,
+// the default is the sender account
+func (d *Deployer) getRemoteRecipient(transfer Transfer) (string, error) {
+	name := transfer.Recipient
+	if name == "" {
+		name = d.from
+	}
+
+	address, found := d.remotes[transfer.Chain][name]
+	if found {
+		return address, nil
+	}
+
+	// any other value is an address of the remote chain
+	if transfer.Recipient != "" && !strings.Contains(name, " ") {
+		_, isAccount := d.accounts[name]
+		if !isAccount {
+			return name, nil
+		}
+	}
+
+	err := fmt.Errorf("recipient not found")
+	d.logger.Err(err).
+		Str("chain", transfer.Chain).
+		Str("recipient", name).
+		Msg("")
+	return "", err
+}
+
+// checkRecipientChain rejects plan contracts on other chains as recipients,
+// denoms are minted on the default chain, use transfers instead
+func (d *Deployer) checkRecipientChain(name string) error {
+	for _, contracts := range d.plan.Contracts {
+		for _, contract := range contracts {
+			if contract.Name != name {
+				continue
+			}
+
+			chainId := d.getContractChain(contract)
+			if chainId != d.defaultChain {
+				err := fmt.Errorf("recipient contract on another chain")
+				d.logger.Err(err).
+					Str("recipient", name).
+					Str("chain", chainId).
+					Msg("")
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d *Deployer) isPlanContract(name string) bool {
+	for _, contracts := range d.plan.Contracts {
+		for _, contract := range contracts {
+			if contract.Name == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func sortedNames(items map[string]string) []string {
+	names := []string{}
+	for name := range items {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
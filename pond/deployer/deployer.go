@@ -1,13 +1,12 @@
 package deployer
 
 import (
-	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"html/template"
 	"net/http"
 	"net/url"
 	"os"
@@ -18,41 +17,58 @@ import (
 	"sync"
 
 	"pond/pond/chain/node"
+	"pond/pond/client"
+	"pond/pond/globals"
 	"pond/pond/registry"
 	"pond/utils"
 
 	"github.com/rs/zerolog"
-	"gopkg.in/yaml.v2"
 )
 
 type Deployer struct {
-	logger    zerolog.Logger
-	node      node.Node
-	Denoms    map[string]Denom
-	Contracts map[string]Contract
-	CodeIds   map[string]string
-	addresses map[string]struct{}
-	codes     map[string]string
-	plan      Plan
-	address   string
-	registry  *registry.Registry
-	apiUrl    string
-	home      string
-	accounts  []string // test accounts for minting assets
+	logger       zerolog.Logger
+	node         node.Node
+	Denoms       map[string]Denom
+	Contracts    map[string]Contract
+	CodeIds      map[string]string
+	addresses    map[string]struct{}
+	labels       map[string]client.ContractInfo // deployed contracts by label
+	codes        map[string]string
+	plan         Plan
+	from         string // sender account name
+	address      string // sender address
+	registry     *registry.Registry
+	sources      map[string]string // source schemes and their api urls
+	home         string
+	accounts     map[string]string            // account names and addresses
+	remotes      map[string]map[string]string // chain id -> account name -> address
+	channels     map[string]client.Channel    // transfer channels by source/counterparty chain id
+	DryRun       bool                         // print the plan instead of broadcasting
+	Offline      bool                         // only use local and cached codes
+	From         string                       // sender account, overrides the plan sender
+	Vars         map[string]string            // plan variables, set with --set
+	chainId      string                       // chain of the node in use
+	defaultChain string                       // chain of denoms and contracts without chain
+	prefix       string                       // bech32 prefix of the chain in use
+	chains       map[string]*target           // other chains running cosmwasm
 }
 
 type Plan struct {
-	Denoms    []Denom           `yaml:"denoms"`
-	Codes     map[string]string `yaml:"codes"`
-	Contracts [][]Contract      `yaml:"contracts"`
-	Names     []string          // holds plan file names, used only for logging
+	Sender      string                `yaml:"sender"` // account name
+	Chain       string                `yaml:"chain"`  // default chain of the plan contracts
+	Admin       string                `yaml:"admin"`  // default admin of the plan contracts
+	Denoms      []Denom               `yaml:"denoms"`
+	Codes       map[string]string     `yaml:"codes"`
+	Permissions map[string]Permission `yaml:"permissions"` // by code name
+	Contracts   [][]Contract          `yaml:"contracts"`
+	Names       []string              // holds plan file names, used only for logging
 }
 
 type CodeMsg struct {
-	Type       string           `json:"@type"`
-	Sender     string           `json:"sender"`
-	Code       string           `json:"wasm_byte_code"`
-	Permission *json.RawMessage `json:"instantiate_permission"`
+	Type       string        `json:"@type"`
+	Sender     string        `json:"sender"`
+	Code       string        `json:"wasm_byte_code"`
+	Permission *AccessConfig `json:"instantiate_permission"`
 }
 
 type DenomMsg struct {
@@ -73,6 +89,21 @@ type ContractMsg struct {
 	FixMsg bool            `json:"fix_msg"`
 }
 
+type MigrateMsg struct {
+	Type     string          `json:"@type"`
+	Sender   string          `json:"sender"`
+	Contract string          `json:"contract"`
+	CodeId   string          `json:"code_id"`
+	Msg      json.RawMessage `json:"msg"`
+}
+
+type AdminMsg struct {
+	Type     string `json:"@type"`
+	Sender   string `json:"sender"`
+	Admin    string `json:"new_admin,omitempty"`
+	Contract string `json:"contract"`
+}
+
 type ActionMsg struct {
 	Type     string          `json:"@type"`
 	Sender   string          `json:"sender"`
@@ -88,12 +119,45 @@ type MintMsg struct {
 	Recipient string `json:"recipient"`
 }
 
+type MetadataMsg struct {
+	Type     string       `json:"@type"`
+	Sender   string       `json:"sender"`
+	Metadata BankMetadata `json:"metadata"`
+}
+
+type BankMetadata struct {
+	Description string      `json:"description"`
+	DenomUnits  []DenomUnit `json:"denom_units"`
+	Base        string      `json:"base"`
+	Display     string      `json:"display"`
+	Name        string      `json:"name"`
+	Symbol      string      `json:"symbol"`
+}
+
+type DenomUnit struct {
+	Denom    string   `json:"denom"`
+	Exponent uint32   `json:"exponent"`
+	Aliases  []string `json:"aliases"`
+}
+
+type TransferMsg struct {
+	Type             string `json:"@type"`
+	SourcePort       string `json:"source_port"`
+	SourceChannel    string `json:"source_channel"`
+	Token            Funds  `json:"token"`
+	Sender           string `json:"sender"`
+	Receiver         string `json:"receiver"`
+	TimeoutTimestamp string `json:"timeout_timestamp"`
+	Memo             string `json:"memo"`
+}
+
 func NewDeployer(
 	logger zerolog.Logger,
 	home string,
 	node node.Node,
-	apiUrl string,
-	accounts []string,
+	sources map[string]string,
+	accounts map[string]string,
+	remotes map[string]map[string]string,
 	registry *registry.Registry,
 ) (Deployer, error) {
 	logger.Debug().Msg("create deployer")
@@ -102,82 +166,115 @@ func NewDeployer(
 		logger: logger,
 		node:   node,
 		plan: Plan{
-			Denoms:    []Denom{},
-			Contracts: [][]Contract{},
-			Codes:     map[string]string{},
+			Denoms:      []Denom{},
+			Contracts:   [][]Contract{},
+			Codes:       map[string]string{},
+			Permissions: map[string]Permission{},
 		},
 		codes:     map[string]string{},
 		Denoms:    map[string]Denom{},
 		Contracts: map[string]Contract{},
 		CodeIds:   map[string]string{},
-		address:   "kujira1k3g54c2sc7g9mgzuzaukm9pvuzcjqy92nk9wse",
 		addresses: map[string]struct{}{},
-		apiUrl:    apiUrl,
+		labels:    map[string]client.ContractInfo{},
+		sources:   sources,
 		home:      home,
 		accounts:  accounts,
+		remotes:   remotes,
+		channels:  map[string]client.Channel{},
 		registry:  registry,
+		chainId:   node.ChainId,
+		prefix:    globals.Chains[node.Type].Prefix,
+		chains:    map[string]*target{},
 	}
 
+	deployer.defaultChain = deployer.chainId
+
 	return deployer, nil
 }
 
 func (d *Deployer) Deploy(filenames []string) error {
-	err := d.UpdateDeployedCodes()
+	err := d.UseSender("")
+	if err != nil {
+		return err
+	}
+
+	err = d.UpdateDeployedCodes()
 	if err != nil {
 		return d.error(err)
 	}
 
-	for _, filename := range filenames {
-		info, err := os.Stat(filename)
+	files, err := ExpandFiles(d.logger, filenames)
+	if err != nil {
+		return err
+	}
+
+	for _, filename := range files {
+		fileType, err := d.FileType(filename)
 		if err != nil {
-			return d.error(err)
+			return err
 		}
 
-		if info.IsDir() {
-			return fmt.Errorf("not yet implemented")
+		switch fileType {
+		case "plan":
+			err = d.LoadPlanFile(filename)
+		case "wasm":
+			err = d.DeployWasmFile(filename)
 		}
 
-		file, err := os.Open(filename)
 		if err != nil {
-			return d.error(err)
+			return err
 		}
+	}
+
+	if len(d.plan.Denoms)+len(d.plan.Contracts) == 0 {
+		d.logger.Debug().Msg("no plan tasks found")
+		return nil
+	}
 
-		defer file.Close()
+	return d.DeployPlan()
+}
 
-		buf := make([]byte, 512)
+// FileType detects wasm and plan files by their content, other files return
+// an empty type
+func (d *Deployer) FileType(filename string) (string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", d.error(err)
+	}
 
-		_, err = file.Read(buf)
-		if err != nil {
-			return d.error(err)
-		}
+	defer file.Close()
 
-		contentType := http.DetectContentType(buf)
+	buf := make([]byte, 512)
 
-		switch contentType {
-		case "text/plain; charset=utf-8", "application/octet-stream":
-			// return d.DeployPlanfiles([]string{filename})
-			err := d.LoadPlanFile(filename)
-			if err != nil {
-				return err
-			}
-		case "application/wasm":
-			err = d.DeployWasmFile(filename)
-			if err != nil {
-				return err
-			}
-		default:
-			d.logger.Warn().
-				Str("type", contentType).
-				Msg("type not supported")
-		}
+	_, err = file.Read(buf)
+	if err != nil {
+		return "", d.error(err)
 	}
 
-	if len(d.plan.Denoms)+len(d.plan.Contracts) == 0 {
-		d.logger.Debug().Msg("no plan tasks found")
-		return nil
+	contentType := http.DetectContentType(buf)
+
+	switch contentType {
+	case "text/plain; charset=utf-8", "application/octet-stream":
+		return "plan", nil
+	case "application/wasm":
+		return "wasm", nil
+	default:
+		d.logger.Warn().
+			Str("type", contentType).
+			Msg("type not supported")
+		return "", nil
 	}
+}
 
-	return d.DeployPlan()
+// ResetPlan removes all loaded plan files, so they can be loaded again
+func (d *Deployer) ResetPlan() {
+	d.plan = Plan{
+		Denoms:      []Denom{},
+		Contracts:   [][]Contract{},
+		Codes:       map[string]string{},
+		Permissions: map[string]Permission{},
+	}
 }
 
 func (d *Deployer) DeployWasmFile(filename string) error {
@@ -188,6 +285,68 @@ func (d *Deployer) DeployWasmFile(filename string) error {
 		return d.error(err)
 	}
 
+	return d.DeployWasm(d.CodeName(filename), filename, data)
+}
+
+// CodeName returns the name of a wasm file. Plan and registry entries with
+// the file as source take precedence over its basename.
+func (d *Deployer) CodeName(filename string) string {
+	path, err := filepath.Abs(filename)
+	if err != nil {
+		path = filename
+	}
+
+	sources := map[string]string{}
+	for name, code := range d.registry.Codes() {
+		sources[name] = code.Source
+	}
+
+	for name, source := range d.plan.Codes {
+		sources[name] = source
+	}
+
+	names := []string{}
+	for name := range sources {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		source := strings.TrimPrefix(sources[name], "file://")
+		if source == sources[name] {
+			continue
+		}
+
+		source, err := filepath.Abs(source)
+		if err == nil && source == path {
+			return name
+		}
+	}
+
+	return strings.Replace(filepath.Base(filename), ".", "_", -1)
+}
+
+// DeployWasm registers the code under the given name and deploys it, if it
+// hasn't been yet. Sources are absolute, the registry is used from any
+// directory.
+func (d *Deployer) DeployWasm(name, filename string, data []byte) error {
+	if !d.DryRun {
+		path, err := filepath.Abs(filename)
+		if err != nil {
+			return d.error(err)
+		}
+
+		err = d.registry.Set(name, registry.Code{
+			Checksum: utils.Sha256(data),
+			Source:   "file://" + path,
+			Code:     data,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	id, deployed := d.getCodeId(data)
 
 	if deployed {
@@ -196,26 +355,22 @@ func (d *Deployer) DeployWasmFile(filename string) error {
 		return nil
 	}
 
-	name := strings.Replace(filepath.Base(filename), ".", "_", -1)
-
-	err = d.registry.Set(name, registry.Code{
-		Checksum: utils.Sha256(data),
-		Source:   "file://" + filename,
-		Code:     data,
-	})
-	if err != nil {
-		return err
+	if d.DryRun {
+		checksum := utils.Sha256(data)
+		id := d.predictCodeId(name, checksum)
+		fmt.Printf("store code %s as code %s (%s)\n", name, id, checksum)
+		return nil
 	}
 
-	err = d.DeployCode(data)
+	permission, err := d.GetPermission(name)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return d.DeployCode(data, permission)
 }
 
-func (d *Deployer) DeployCode(data []byte) error {
+func (d *Deployer) DeployCode(data []byte, permission *AccessConfig) error {
 	d.logger.Debug().Msg("deploy code")
 
 	filename, err := d.node.CreateTemp(data, "wasm")
@@ -225,7 +380,12 @@ func (d *Deployer) DeployCode(data []byte) error {
 
 	args := []string{
 		"wasm", "store", filename,
-		"--from", "deployer", "--gas", "auto", "--gas-adjustment", "1.5",
+		"--from", d.from, "--gas", "auto", "--gas-adjustment", "1.5",
+		"--output", "json",
+	}
+
+	if permission != nil {
+		args = append(args, permission.Flags()...)
 	}
 
 	output, err := d.node.Tx(args)
@@ -238,7 +398,7 @@ func (d *Deployer) DeployCode(data []byte) error {
 		return d.error(err)
 	}
 
-	err = d.node.WaitForTx(hash)
+	_, err = d.node.WaitForTx(hash)
 	if err != nil {
 		return d.error(err)
 	}
@@ -261,7 +421,25 @@ func (d *Deployer) DeployCode(data []byte) error {
 func (d *Deployer) DeployPlan() error {
 	d.logger.Debug().Msg("deploy plan")
 
-	err := d.UpdateDeployedCodes()
+	err := d.UseSender(d.plan.Sender)
+	if err != nil {
+		return err
+	}
+
+	// all chains of the plan need cosmwasm
+	chains := d.getPlanChains()
+	for _, chainId := range chains[1:] {
+		_, found := d.chains[chainId]
+		if !found {
+			err := fmt.Errorf("chain not found or without cosmwasm")
+			d.logger.Err(err).Str("chain", chainId).Msg("")
+			return err
+		}
+	}
+
+	defer d.UseChain("")
+
+	err = d.UpdateDeployedCodes()
 	if err != nil {
 		return d.error(err)
 	}
@@ -340,20 +518,40 @@ func (d *Deployer) DeployPlan() error {
 
 	combinedMsgs := append(codeMsgs, denomMsgs...)
 
-	if len(combinedMsgs) > 0 {
+	if d.DryRun {
+		err = d.PrintSetup(codes, denomMsgs)
+		if err != nil {
+			return err
+		}
+
+		d.simulate(combinedMsgs)
+	} else if len(combinedMsgs) > 0 {
 		err = d.SignAndSend(combinedMsgs)
 		if err != nil {
 			return err
 		}
 	}
 
-	if len(codeMsgs) > 0 {
+	if len(codeMsgs) > 0 && !d.DryRun {
 		err := d.UpdateDeployedCodes()
 		if err != nil {
 			return err
 		}
 	}
 
+	// codes of contracts on other chains are stored there
+	for _, chainId := range chains[1:] {
+		err := d.deployChainCodes(chainId)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = d.UseChain("")
+	if err != nil {
+		return err
+	}
+
 	// update internal denom map
 	for _, denom := range d.plan.Denoms {
 		d.Denoms[denom.Name] = Denom{
@@ -362,6 +560,13 @@ func (d *Deployer) DeployPlan() error {
 		}
 	}
 
+	for _, denom := range denoms {
+		err := d.SetIbcPaths(denom)
+		if err != nil {
+			return err
+		}
+	}
+
 	for _, contracts := range d.plan.Contracts {
 		for _, contract := range contracts {
 			if len(contract.Creates) == 0 {
@@ -383,46 +588,109 @@ func (d *Deployer) DeployPlan() error {
 
 	total := len(d.plan.Contracts)
 	for i, contracts := range d.plan.Contracts {
-		msgs, err := d.CreateContractMsgs(contracts)
+		step := fmt.Sprintf("%d/%d", i+1, total)
+
+		// stages can span chains, each chain gets its own tx
+		chains, groups := d.groupByChain(contracts)
+		for _, chainId := range chains {
+			err := d.deployStage(step, d.plan.Names[i], chainId, groups[chainId])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = d.UseChain("")
+	if err != nil {
+		return err
+	}
+
+	// mint new denoms to their contract recipients
+	msgs, err := d.CreateDistributionMsgs(d.plan.Denoms)
+	if err != nil {
+		return err
+	}
+
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	if d.DryRun {
+		fmt.Printf("\ndistribute denoms\n")
+		err = d.PrintDenoms(msgs)
 		if err != nil {
 			return err
 		}
 
-		step := fmt.Sprintf("%d/%d", i+1, total)
+		d.simulate(msgs)
+		return nil
+	}
 
-		if len(msgs) == 0 {
-			d.logger.Info().
-				Str("plan", d.plan.Names[i]).
-				Str("step", step).
-				Msg("contracts already deployed")
-			continue
+	d.logger.Info().Msg("distribute denoms")
+
+	return d.SignAndSend(msgs)
+}
+
+// deployStage deploys the contracts of a stage on one chain
+func (d *Deployer) deployStage(
+	step, name, chainId string, contracts []Contract,
+) error {
+	err := d.UseChain(chainId)
+	if err != nil {
+		return err
+	}
+
+	msgs, err := d.CreateContractMsgs(contracts)
+	if err != nil {
+		return err
+	}
+
+	if d.DryRun {
+		if chainId == d.defaultChain {
+			fmt.Printf("\nstage %s (%s)\n", step, name)
+		} else {
+			fmt.Printf("\nstage %s (%s) on %s\n", step, name, chainId)
 		}
 
+		d.PrintContracts(contracts, msgs)
+		d.simulate(msgs)
+		return nil
+	}
+
+	if len(msgs) == 0 {
 		d.logger.Info().
-			Str("plan", d.plan.Names[i]).
+			Str("plan", name).
 			Str("step", step).
-			Msg("deploy contracts")
+			Str("chain", chainId).
+			Msg("contracts already deployed")
+		return nil
+	}
 
-		err = d.SignAndSend(msgs)
-		if err != nil {
-			return err
-		}
+	d.logger.Info().
+		Str("plan", name).
+		Str("step", step).
+		Str("chain", chainId).
+		Msg("deploy contracts")
 
-		for _, contract := range contracts {
-			if len(contract.Creates) == 0 {
-				continue
-			}
+	err = d.SignAndSend(msgs)
+	if err != nil {
+		return err
+	}
 
-			contract, found := d.Contracts[contract.Name]
-			if !found {
-				continue
-			}
+	for _, contract := range contracts {
+		if len(contract.Creates) == 0 {
+			continue
+		}
 
-			for _, denom := range contract.Creates {
-				path := fmt.Sprintf("factory/%s/%s", contract.Address, denom.Nonce)
-				denom.Path = path
-				d.Denoms[denom.Name] = denom
-			}
+		contract, found := d.Contracts[contract.Name]
+		if !found {
+			continue
+		}
+
+		for _, denom := range contract.Creates {
+			path := fmt.Sprintf("factory/%s/%s", contract.Address, denom.Nonce)
+			denom.Path = path
+			d.Denoms[denom.Name] = denom
 		}
 	}
 
@@ -440,9 +708,39 @@ func (d *Deployer) getCodeId(data []byte) (string, bool) {
 
 func (d *Deployer) LoadPlan(data []byte, name string) error {
 	var plan Plan
-	err := json.Unmarshal(data, &plan)
+	var contracts json.RawMessage
+
+	// contracts are decoded separately, they can be staged or flat
+	err := json.Unmarshal(data, &struct {
+		*Plan
+		Contracts *json.RawMessage `json:"contracts"`
+	}{&plan, &contracts})
+	if err == nil {
+		// the plan admin and chain are the defaults of its contracts
+		plan.Contracts, err = d.loadContracts(contracts, plan.Admin, plan.Chain)
+	}
+
 	if err != nil {
-		return d.error(err)
+		d.logger.Err(err).Str("plan", name).Msg("")
+		return err
+	}
+
+	if plan.Sender != "" {
+		if d.plan.Sender != "" && d.plan.Sender != plan.Sender {
+			err := fmt.Errorf("plans use different senders")
+			d.logger.Err(err).
+				Str("plan", name).
+				Str("sender", plan.Sender).
+				Str("other", d.plan.Sender).
+				Msg("")
+			return err
+		}
+
+		d.plan.Sender = plan.Sender
+	}
+
+	for code, permission := range plan.Permissions {
+		d.plan.Permissions[code] = permission
 	}
 
 	// load denom tasks
@@ -466,7 +764,7 @@ func (d *Deployer) LoadPlan(data []byte, name string) error {
 
 	d.plan.Contracts = append(d.plan.Contracts, plan.Contracts...)
 
-	names := make([]string, len(d.plan.Contracts))
+	names := make([]string, len(plan.Contracts))
 	for i := range names {
 		names[i] = name
 	}
@@ -475,6 +773,56 @@ func (d *Deployer) LoadPlan(data []byte, name string) error {
 	return nil
 }
 
+// loadContracts decodes the contract stages of a plan. Flat lists are put
+// into stages by their dependencies.
+func (d *Deployer) loadContracts(
+	data json.RawMessage,
+	admin, chainId string,
+) ([][]Contract, error) {
+	stages := []Stage{}
+
+	if len(data) == 0 {
+		return [][]Contract{}, nil
+	}
+
+	err := json.Unmarshal(data, &stages)
+	if err == nil {
+		contracts := make([][]Contract, len(stages))
+		for i, stage := range stages {
+			contracts[i] = stage
+		}
+
+		setDefaults(contracts, admin, chainId)
+		return contracts, nil
+	}
+
+	contracts := []Contract{}
+
+	// return the error of the staged format, if both fail
+	if json.Unmarshal(data, &contracts) != nil {
+		return nil, err
+	}
+
+	setDefaults([][]Contract{contracts}, admin, chainId)
+
+	return OrderContracts(contracts)
+}
+
+// setDefaults sets the plan admin and chain of all contracts without their
+// own admin and chain
+func setDefaults(stages [][]Contract, admin, chainId string) {
+	for _, contracts := range stages {
+		setChain(contracts, chainId)
+
+		for i := range contracts {
+			if contracts[i].Admin == "" {
+				contracts[i].Admin = admin
+			}
+		}
+	}
+}
+
+// LoadPlanFile loads json, jsonc and yaml plan files
 func (d *Deployer) LoadPlanFile(filename string) error {
 	d.logger.Debug().Str("file", filename).Msg("load plan file")
 
@@ -483,19 +831,45 @@ func (d *Deployer) LoadPlanFile(filename string) error {
 		return d.error(err)
 	}
 
-	name := strings.Replace(filepath.Base(filename), ".json", "", -1)
+	ext := filepath.Ext(filename)
+
+	switch ext {
+	case ".yaml", ".yml":
+		content, err = utils.YamlToJson(content)
+		if err != nil {
+			d.logger.Err(err).Str("file", filename).Msg("")
+			return err
+		}
+	default:
+		content = utils.StripJsonComments(content)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(filename), ext)
 	return d.LoadPlan(content, name)
 }
 
+// BuildAddress returns the instantiate2 address of a contract created by the
+// sender on the chain in use, hash and salt are hex encoded
 func (d *Deployer) BuildAddress(hash, salt string) (string, error) {
-	args := []string{"wasm", "build-address", hash, d.address, salt}
+	checksum, err := hex.DecodeString(hash)
+	if err != nil {
+		return "", d.error(err)
+	}
 
-	output, err := d.node.Query(args)
+	saltBytes, err := hex.DecodeString(salt)
 	if err != nil {
-		return "", err
+		return "", d.error(err)
 	}
 
-	address := strings.TrimSpace(string(output))
+	_, creator, err := utils.Bech32Decode(d.address)
+	if err != nil {
+		return "", d.error(err)
+	}
+
+	address, err := utils.ContractAddress(d.prefix, checksum, creator, saltBytes)
+	if err != nil {
+		return "", d.error(err)
+	}
 
 	return address, nil
 }
@@ -506,11 +880,16 @@ func (d *Deployer) CreateCodeMsgs(codes []registry.Code) ([]json.RawMessage, err
 	msgs := make([]json.RawMessage, len(codes))
 
 	for i, code := range codes {
+		permission, err := d.GetPermission(code.Name)
+		if err != nil {
+			return nil, err
+		}
+
 		msg := CodeMsg{
 			Type:       "/cosmwasm.wasm.v1.MsgStoreCode",
 			Sender:     d.address,
 			Code:       base64.StdEncoding.EncodeToString(code.Code),
-			Permission: nil,
+			Permission: permission,
 		}
 
 		data, err := json.Marshal(msg)
@@ -541,26 +920,24 @@ func (d *Deployer) CreateDenomMsgs(denoms []Denom) ([]json.RawMessage, error) {
 
 		msgs = append(msgs, data)
 
-		if denom.Mint == "" {
-			continue
-		}
+		if denom.Mint != "" {
+			for _, address := range d.getMintRecipients() {
+				data, err := d.createMintMsg(denom, denom.Mint, address)
+				if err != nil {
+					return nil, err
+				}
 
-		for _, address := range d.accounts {
-			data, err = json.Marshal(MintMsg{
-				Type:   "/kujira.denom.MsgMint",
-				Sender: d.address,
-				Amount: Funds{
-					Amount: strings.Replace(denom.Mint, "_", "", -1),
-					Denom:  fmt.Sprintf("factory/%s/%s", d.address, denom.Nonce),
-				},
-				Recipient: address,
-			})
-			if err != nil {
-				return nil, d.error(err)
+				msgs = append(msgs, data)
 			}
+		}
 
-			msgs = append(msgs, data)
+		// metadata, other recipients and transfers
+		extra, err := d.createDenomSetupMsgs(denom)
+		if err != nil {
+			return nil, err
 		}
+
+		msgs = append(msgs, extra...)
 	}
 
 	return msgs, nil
@@ -574,19 +951,12 @@ func (d *Deployer) CreateActionMsg(action Action) (json.RawMessage, error) {
 		return nil, d.error(err)
 	}
 
-	tmpl, err := template.New("").Parse(string(data))
-	if err != nil {
-		return nil, d.error(err)
-	}
-
-	var buffer bytes.Buffer
-
-	err = tmpl.Execute(&buffer, d)
+	data, err = d.renderJSON(data)
 	if err != nil {
-		return nil, d.error(err)
+		return nil, err
 	}
 
-	err = json.Unmarshal(buffer.Bytes(), &action)
+	err = json.Unmarshal(data, &action)
 	if err != nil {
 		return nil, err
 	}
@@ -642,52 +1012,49 @@ func (d *Deployer) CreateContractMsgs(
 
 		contract.Address = address
 
+		// contracts are identified by label, so code changes don't create
+		// a new contract
+		existing, deployed := d.labels[contract.Label]
+		if deployed {
+			contract.Address = existing.Address
+		}
+
 		// append already, it can't be used if something breaks later
 		d.Contracts[contract.Name] = contract
 
-		_, found = d.addresses[address]
-		if found {
-			d.logger.Debug().
-				Str("address", address).
-				Msg("contract already deployed")
-			continue
-		}
-
 		if contract.Allocate {
 			continue
 		}
 
-		data, err := json.Marshal(contract.Msg)
-		if err != nil {
-			return nil, d.error(err)
-		}
+		if deployed {
+			updates, err := d.CreateUpdateMsgs(contract, existing, id)
+			if err != nil {
+				return nil, err
+			}
 
-		tmpl, err := template.New(address).Parse(string(data))
-		if err != nil {
-			return nil, d.error(err)
+			msgs = append(msgs, updates...)
+			continue
 		}
 
-		var buffer bytes.Buffer
-
-		err = tmpl.Execute(&buffer, d)
+		msg, err := d.renderMsg(contract.Msg)
 		if err != nil {
-			return nil, d.error(err)
+			return nil, err
 		}
 
-		msg, err := d.Convert(buffer.Bytes())
+		funds, err := d.StringToFunds(contract.Funds)
 		if err != nil {
 			return nil, err
 		}
 
-		funds, err := d.StringToFunds(contract.Funds)
+		admin, err := d.GetAdmin(contract.Admin)
 		if err != nil {
 			return nil, err
 		}
 
-		data, err = json.Marshal(ContractMsg{
+		data, err := json.Marshal(ContractMsg{
 			Type:   "/cosmwasm.wasm.v1.MsgInstantiateContract2",
 			Sender: d.address,
-			Admin:  d.address,
+			Admin:  admin,
 			CodeId: id,
 			Label:  contract.Label,
 			Msg:    msg,
@@ -718,9 +1085,106 @@ func (d *Deployer) CreateContractMsgs(
 	return msgs, nil
 }
 
+// CreateUpdateMsgs migrates an already deployed contract if its code
+// changed and updates its admin
+func (d *Deployer) CreateUpdateMsgs(
+	contract Contract, existing client.ContractInfo, codeId string,
+) ([]json.RawMessage, error) {
+	msgs := []json.RawMessage{}
+
+	logger := d.logger.With().
+		Str("label", contract.Label).
+		Str("address", existing.Address).
+		Logger()
+
+	if existing.CodeId == codeId {
+		logger.Debug().Msg("contract already deployed")
+	} else if existing.Admin != d.address {
+		logger.Warn().
+			Str("admin", existing.Admin).
+			Msg("code changed, but contract can't be migrated by deployer")
+	} else {
+		logger.Info().
+			Str("from", existing.CodeId).
+			Str("to", codeId).
+			Msg("migrate contract")
+
+		migrate := contract.Migrate
+		if migrate == nil {
+			migrate = map[string]json.RawMessage{}
+		}
+
+		msg, err := d.renderMsg(migrate)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := json.Marshal(MigrateMsg{
+			Type:     "/cosmwasm.wasm.v1.MsgMigrateContract",
+			Sender:   d.address,
+			Contract: existing.Address,
+			CodeId:   codeId,
+			Msg:      msg,
+		})
+		if err != nil {
+			return nil, d.error(err)
+		}
+
+		msgs = append(msgs, data)
+	}
+
+	admin, err := d.GetAdmin(contract.Admin)
+	if err != nil {
+		return nil, err
+	}
+
+	if admin == existing.Admin {
+		return msgs, nil
+	}
+
+	if existing.Admin != d.address {
+		logger.Warn().
+			Str("admin", existing.Admin).
+			Msg("admin changed, but contract admin isn't the deployer")
+		return msgs, nil
+	}
+
+	logger.Info().Str("admin", admin).Msg("update admin")
+
+	msg := AdminMsg{
+		Type:     "/cosmwasm.wasm.v1.MsgUpdateAdmin",
+		Sender:   d.address,
+		Admin:    admin,
+		Contract: existing.Address,
+	}
+
+	if admin == "" {
+		msg.Type = "/cosmwasm.wasm.v1.MsgClearAdmin"
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, d.error(err)
+	}
+
+	return append(msgs, data), nil
+}
+
+// renderMsg executes the templates of a contract msg
+func (d *Deployer) renderMsg(msg map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, d.error(err)
+	}
+
+	return d.renderJSON(data)
+}
+
 func (d *Deployer) CreateDenom(nonce string) error {
 	d.logger.Info().Str("nonce", nonce).Msg("create denom")
-	args := []string{"denom", "create-denom", nonce, "--from", "deployer"}
+	args := []string{
+		"denom", "create-denom", nonce, "--from", d.from, "--output", "json",
+	}
 
 	output, err := d.node.Tx(args)
 	if err != nil {
@@ -732,7 +1196,7 @@ func (d *Deployer) CreateDenom(nonce string) error {
 		return d.error(err)
 	}
 
-	err = d.node.WaitForTx(hash)
+	_, err = d.node.WaitForTx(hash)
 	if err != nil {
 		return err
 	}
@@ -748,48 +1212,32 @@ func (d *Deployer) error(err error) error {
 func (d *Deployer) UpdateDeployedCodes() error {
 	d.logger.Debug().Msg("get deployed codes")
 
-	args := []string{"wasm", "list-code", "--output", "json"}
+	c := d.node.Client()
 
-	var info struct {
-		CodeInfos []struct {
-			CodeId   string `json:"code_id"`
-			DataHash string `json:"data_hash"`
-		} `json:"code_infos"`
-		Pagination struct {
-			NextKey string `json:"next_key"`
-		} `json:"pagination"`
-	}
-
-	key := "dummy"
-	for key != "" {
-		args := args
-		if key != "dummy" {
-			args = append(args, []string{"--page-key", key}...)
-		}
-
-		output, err := d.node.Query(args)
+	key := ""
+	for {
+		codes, next, err := c.Codes(key)
 		if err != nil {
-			return err
+			return d.error(err)
 		}
 
-		err = json.Unmarshal(output, &info)
-		if err != nil {
-			return err
+		for _, code := range codes {
+			d.codes[code.Checksum] = code.Id
 		}
 
-		for _, code := range info.CodeInfos {
-			d.codes[code.DataHash] = code.CodeId
+		if next == "" {
+			break
 		}
 
-		for name, code := range d.registry.Codes() {
-			codeId, found := d.codes[code.Checksum]
-			if !found {
-				continue
-			}
-			d.CodeIds[name] = codeId
-		}
+		key = next
+	}
 
-		key = info.Pagination.NextKey
+	for name, code := range d.registry.Codes() {
+		codeId, found := d.codes[code.Checksum]
+		if !found {
+			continue
+		}
+		d.CodeIds[name] = codeId
 	}
 
 	return nil
@@ -798,84 +1246,138 @@ func (d *Deployer) UpdateDeployedCodes() error {
 func (d *Deployer) UpdateDeployedContracts() error {
 	d.logger.Debug().Msg("update deployed contracts")
 
-	args := []string{
-		"wasm", "list-contracts-by-creator", d.address, "--output", "json",
-	}
-
-	var info struct {
-		Addresses  []string `json:"contract_addresses"`
-		Pagination struct {
-			NextKey string `json:"next_key"`
-		} `json:"pagination"`
-	}
-
-	key := "dummy"
-	for key != "" {
-		args := args
-		if key != "dummy" {
-			args = append(args, []string{"--page-key", key}...)
-		}
+	c := d.node.Client()
 
-		output, err := d.node.Query(args)
+	key := ""
+	for {
+		addresses, next, err := c.ContractsByCreator(d.address, key)
 		if err != nil {
 			return d.error(err)
 		}
 
-		err = json.Unmarshal(output, &info)
-		if err != nil {
-			return d.error(err)
-		}
+		for _, address := range addresses {
+			_, found := d.addresses[address]
+			if found {
+				continue
+			}
+
+			info, err := c.Contract(address)
+			if err != nil {
+				return d.error(err)
+			}
 
-		for _, address := range info.Addresses {
 			d.addresses[address] = struct{}{}
+			d.labels[info.Label] = info
 		}
 
-		key = info.Pagination.NextKey
+		if next == "" {
+			break
+		}
+
+		key = next
 	}
 
 	return nil
 }
 
-func (d *Deployer) SignAndSend(msgs []json.RawMessage) error {
+// getMintRecipients returns the addresses of the deployer and all test
+// accounts, sorted by name
+func (d *Deployer) getMintRecipients() []string {
+	names := []string{}
+	for name := range d.accounts {
+		if strings.HasPrefix(name, "test") || name == "deployer" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	addresses := make([]string, len(names))
+	for i, name := range names {
+		addresses[i] = d.accounts[name]
+	}
+
+	return addresses
+}
+
+// GetAdmin resolves the admin of a contract. Empty defaults to the deployer,
+// "none" means no admin. Account names and names of plan contracts are
+// resolved to their address.
+func (d *Deployer) GetAdmin(admin string) (string, error) {
+	switch admin {
+	case "":
+		return d.address, nil
+	case "none":
+		return "", nil
+	}
+
+	address, found := d.getAccount(admin)
+	if found {
+		return address, nil
+	}
+
+	contract, found := d.Contracts[admin]
+	if found {
+		return contract.Address, nil
+	}
+
+	if d.isAddress(admin) {
+		return admin, nil
+	}
+
+	err := fmt.Errorf("admin not found")
+	d.logger.Err(err).Str("admin", admin).Msg("")
+	return "", err
+}
+
+// Sign signs the msgs with the deployer and returns the tx filename
+func (d *Deployer) Sign(msgs []json.RawMessage) (string, error) {
 	data, err := json.Marshal(msgs)
 	if err != nil {
-		return d.error(err)
+		return "", d.error(err)
 	}
 
 	msg, err := utils.NewTxMsg(data)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	d.logger.Trace().Msg(string(data))
 
 	unsigned, err := d.node.CreateTemp(msg, "tx")
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	// sign
 	d.logger.Debug().Msg("sign tx")
 
 	output, err := d.node.Tx([]string{
 		"sign", unsigned,
-		"--from", "deployer", "--gas", "1000000000",
+		"--from", d.from, "--gas", "1000000000",
 	})
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	signed, err := d.node.CreateTemp(output, "tx")
 	if err != nil {
-		return d.error(err)
+		return "", d.error(err)
+	}
+
+	return signed, nil
+}
+
+func (d *Deployer) SignAndSend(msgs []json.RawMessage) error {
+	signed, err := d.Sign(msgs)
+	if err != nil {
+		return err
 	}
 
 	// broadcast
 	d.logger.Debug().Msg("broadcast tx")
 
-	output, err = d.node.Tx([]string{
+	output, err := d.node.Tx([]string{
 		"broadcast", signed,
-		"--gas", "auto", "--gas-adjustment", "1.5",
+		"--gas", "auto", "--gas-adjustment", "1.5", "--output", "json",
 	})
 	if err != nil {
 		return err
@@ -886,7 +1388,7 @@ func (d *Deployer) SignAndSend(msgs []json.RawMessage) error {
 		return d.error(err)
 	}
 
-	err = d.node.WaitForTx(hash)
+	_, err = d.node.WaitForTx(hash)
 	if err != nil {
 		return d.error(err)
 	}
@@ -894,57 +1396,161 @@ func (d *Deployer) SignAndSend(msgs []json.RawMessage) error {
 	return nil
 }
 
+// GetCode loads the code from its source. Downloaded codes are cached by
+// their checksum.
 func (d *Deployer) GetCode(code registry.Code) ([]byte, error) {
+	if strings.HasPrefix(code.Source, "file://") {
+		data, err := d.loadCode(code)
+		if err != nil {
+			return nil, err
+		}
+
+		return data, d.checkCode(code, data)
+	}
+
+	data, found := d.getCachedCode(code.Checksum)
+	if found {
+		d.logger.Debug().Str("source", code.Source).Msg("load code from cache")
+		return data, nil
+	}
+
+	if d.Offline {
+		err := fmt.Errorf("code not cached, run pond registry fetch")
+		d.logger.Err(err).Str("source", code.Source).Msg("")
+		return nil, err
+	}
+
+	data, err := d.loadCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.checkCode(code, data)
+	if err != nil {
+		return nil, err
+	}
+
+	d.setCachedCode(data)
+
+	return data, nil
+}
+
+// loadCode reads or downloads the code from its source, bypassing the cache
+func (d *Deployer) loadCode(code registry.Code) ([]byte, error) {
 	parts, err := url.Parse(code.Source)
 	if err != nil {
 		return nil, d.error(err)
 	}
 
-	var data []byte
-
 	switch parts.Scheme {
-	case "kaiyo-1":
-		d.logger.Info().
-			Str("code_id", parts.Host).
-			Msg("download code from mainnet")
-
-		data, err = d.GetCodeFromApi(parts.Host)
+	case "file":
+		data, err := os.ReadFile(parts.Path)
 		if err != nil {
 			return nil, d.error(err)
 		}
 
-	case "file":
-		data, err = os.ReadFile(parts.Path)
+		return data, nil
+
+	case "https", "http":
+		// downloads must be verified
+		if code.Checksum == "" {
+			err := fmt.Errorf("checksum required")
+			d.logger.Err(err).Str("source", code.Source).Msg("")
+			return nil, err
+		}
+
+		d.logger.Info().Str("url", code.Source).Msg("download code")
+
+		data, err := utils.HttpGet(d.logger, code.Source)
 		if err != nil {
 			return nil, d.error(err)
 		}
+
+		return data, nil
+
 	default:
-		err = fmt.Errorf("scheme not supported")
-		d.logger.Err(err).Str("scheme", parts.Scheme).Msg("")
-		return nil, err
+		apiUrl, found := d.sources[parts.Scheme]
+		if !found {
+			err = fmt.Errorf("scheme not supported")
+			d.logger.Err(err).Str("scheme", parts.Scheme).Msg("")
+			return nil, err
+		}
+
+		d.logger.Info().
+			Str("chain", parts.Scheme).
+			Str("code_id", parts.Host).
+			Msg("download code")
+
+		data, err := d.GetCodeFromApi(apiUrl, parts.Host)
+		if err != nil {
+			return nil, d.error(err)
+		}
+
+		return data, nil
 	}
+}
 
+// checkCode verifies the checksum of the code, if the registry has one
+func (d *Deployer) checkCode(code registry.Code, data []byte) error {
 	if len(data) == 0 {
-		err = fmt.Errorf("failed loading code")
-		return nil, d.error(err)
+		err := fmt.Errorf("failed loading code")
+		return d.error(err)
 	}
 
 	// if no checksum is defined, don't try to check it
 	if code.Checksum == "" {
-		return data, nil
+		return nil
 	}
 
 	checksum := utils.Sha256(data)
 	if !strings.EqualFold(checksum, code.Checksum) {
-		err = fmt.Errorf("checksum mismatch")
+		err := fmt.Errorf("checksum mismatch")
 		d.logger.Err(err).
 			Str("source", code.Source).
 			Str("checksum", code.Checksum).
 			Msg("")
-		return nil, err
+		return err
 	}
 
-	return data, nil
+	return nil
+}
+
+func (d *Deployer) getCachedCode(checksum string) ([]byte, bool) {
+	if checksum == "" {
+		return nil, false
+	}
+
+	filename := filepath.Join(d.home, "cache", "codes", strings.ToUpper(checksum))
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, false
+	}
+
+	// ignore broken cache entries
+	if !strings.EqualFold(utils.Sha256(data), checksum) {
+		return nil, false
+	}
+
+	return data, true
+}
+
+// setCachedCode stores the code, failures only cause another download
+func (d *Deployer) setCachedCode(data []byte) {
+	dir := filepath.Join(d.home, "cache", "codes")
+
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		d.logger.Warn().Err(err).Msg("failed creating code cache")
+		return
+	}
+
+	filename := filepath.Join(dir, strings.ToUpper(utils.Sha256(data)))
+
+	err = os.WriteFile(filename, data, 0o644)
+	if err != nil {
+		d.logger.Warn().Err(err).Msg("failed caching code")
+	}
 }
 
 func (d *Deployer) GetMissingCodes() ([]registry.Code, error) {
@@ -972,6 +1578,11 @@ func (d *Deployer) GetMissingCodes() ([]registry.Code, error) {
 
 	for _, contracts := range d.plan.Contracts {
 		for _, contract := range contracts {
+			// codes are stored on the chains of their contracts
+			if d.getContractChain(contract) != d.chainId {
+				continue
+			}
+
 			code, err := d.registry.Get(contract.Code)
 			if err != nil {
 				return nil, err
@@ -996,6 +1607,9 @@ func (d *Deployer) GetMissingCodes() ([]registry.Code, error) {
 
 	codes := []registry.Code{}
 
+	// the first error cancels the other downloads
+	var first error
+
 	for _, code := range missing {
 		wg.Add(1)
 		go func(code registry.Code) {
@@ -1009,6 +1623,12 @@ func (d *Deployer) GetMissingCodes() ([]registry.Code, error) {
 
 			data, err := d.GetCode(code)
 			if err != nil {
+				mtx.Lock()
+				if first == nil {
+					first = err
+				}
+				mtx.Unlock()
+
 				cancel()
 				return
 			}
@@ -1023,15 +1643,15 @@ func (d *Deployer) GetMissingCodes() ([]registry.Code, error) {
 
 	wg.Wait()
 
-	if ctx.Err() != nil {
-		return nil, ctx.Err()
+	if first != nil {
+		return nil, first
 	}
 
 	return codes, nil
 }
 
-func (d *Deployer) GetCodeFromApi(codeId string) ([]byte, error) {
-	url := d.apiUrl + "/cosmwasm/wasm/v1/code/" + codeId
+func (d *Deployer) GetCodeFromApi(apiUrl, codeId string) ([]byte, error) {
+	url := strings.TrimSuffix(apiUrl, "/") + "/cosmwasm/wasm/v1/code/" + codeId
 
 	data, err := utils.HttpGet(d.logger, url)
 	if err != nil {
@@ -1061,31 +1681,33 @@ func (d *Deployer) GetCodeFromApi(codeId string) ([]byte, error) {
 func (d *Deployer) GetDenomsFromCreator(address string) ([]string, error) {
 	d.logger.Debug().Msg("get available denoms")
 
-	args := []string{"denom", "denoms-from-creator", address}
-
-	output, err := d.node.Query(args)
-	if err != nil {
-		return nil, d.error(err)
-	}
-
-	var response struct {
-		Denoms []string `yaml:"denoms"`
-	}
+	c := d.node.Client()
 
-	err = yaml.Unmarshal(output, &response)
+	denoms, err := c.DenomsFromCreator(address)
 	if err != nil {
 		return nil, d.error(err)
 	}
 
-	return response.Denoms, nil
+	return denoms, nil
 }
 
 func (d *Deployer) GetDeployedCodes() ([]registry.Code, error) {
 	codes := []registry.Code{}
 	names := map[string]string{}
 
-	for name, code := range d.registry.Codes() {
-		names[code.Checksum] = name
+	// versions can share a checksum, prefer the first name
+	registered := []string{}
+	for name := range d.registry.Codes() {
+		registered = append(registered, name)
+	}
+	sort.Strings(registered)
+
+	for _, name := range registered {
+		checksum := d.registry.Codes()[name].Checksum
+		_, found := names[checksum]
+		if !found {
+			names[checksum] = name
+		}
 	}
 
 	for checksum, id := range d.codes {
@@ -1111,12 +1733,19 @@ func (d *Deployer) GetDeployedContracts() ([]Contract, error) {
 	contracts := []Contract{}
 
 	for _, contract := range d.Contracts {
+		// only the address of allocated contracts is known
+		if contract.Allocate {
+			continue
+		}
+
 		code, err := d.registry.Get(contract.Code)
 		if err != nil {
 			return nil, err
 		}
 
-		id, found := d.codes[code.Checksum]
+		contract.Chain = d.getContractChain(contract)
+
+		id, found := d.getChainCodes(contract.Chain)[code.Checksum]
 		if !found {
 			err := fmt.Errorf("code not deployed")
 			d.logger.Err(err).Str("name", contract.Code).Msg("")
@@ -1137,20 +1766,11 @@ func (d *Deployer) StringToFunds(str string) ([]Funds, error) {
 		return funds, nil
 	}
 
-	tmpl, err := template.New("").Parse(str)
-	if err != nil {
-		return nil, d.error(err)
-	}
-
-	var buffer bytes.Buffer
-
-	err = tmpl.Execute(&buffer, d)
+	str, err := d.render(str)
 	if err != nil {
-		return nil, d.error(err)
+		return nil, err
 	}
 
-	str = buffer.String()
-
 	regex := regexp.MustCompile(`^(\d+)([/A-Za-z0-9]+)$`)
 
 	for _, part := range strings.Split(str, ",") {
@@ -1170,22 +1790,3 @@ func (d *Deployer) StringToFunds(str string) ([]Funds, error) {
 
 	return funds, nil
 }
-
-func (d *Deployer) Convert(data []byte) ([]byte, error) {
-	raw := json.RawMessage(data)
-
-	data, err := json.Marshal(raw)
-	if err != nil {
-		return nil, d.error(err)
-	}
-
-	buffer := new(bytes.Buffer)
-	err = json.Compact(buffer, data)
-	if err != nil {
-		return nil, d.error(err)
-	}
-
-	regex := regexp.MustCompile(`"((\d+)\s*\|\s*int\s*)"`)
-
-	return []byte(regex.ReplaceAllString(string(data), "$2")), nil
-}
@@ -0,0 +1,191 @@
+package deployer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"pond/pond/registry"
+)
+
+// PrintSetup prints the codes to be stored and the denoms to be created and
+// minted. Missing codes get the code ids they would be stored with.
+func (d *Deployer) PrintSetup(
+	codes []registry.Code, denomMsgs []json.RawMessage,
+) error {
+	d.PrintCodes(codes)
+
+	fmt.Println("denoms")
+
+	if len(denomMsgs) == 0 {
+		fmt.Println("  all denoms created")
+	}
+
+	return d.PrintDenoms(denomMsgs)
+}
+
+// PrintCodes prints the codes to be stored with the code ids they would be
+// stored with
+func (d *Deployer) PrintCodes(codes []registry.Code) {
+	fmt.Println("codes")
+
+	if len(codes) == 0 {
+		fmt.Println("  all codes deployed")
+	}
+
+	// codes are stored in the order of their msgs
+	for _, code := range codes {
+		id := d.predictCodeId(code.Name, code.Checksum)
+
+		fmt.Printf("  store %s as code %s (%s)\n", code.Name, id, code.Checksum)
+	}
+}
+
+// predictCodeId assigns the next free code id to a code that isn't stored
+// yet, so later dry-run steps can refer to it
+func (d *Deployer) predictCodeId(name, checksum string) string {
+	next := 1
+	for _, id := range d.codes {
+		value, err := strconv.Atoi(id)
+		if err != nil {
+			continue
+		}
+
+		if value >= next {
+			next = value + 1
+		}
+	}
+
+	id := strconv.Itoa(next)
+
+	d.codes[checksum] = id
+	d.CodeIds[name] = id
+
+	return id
+}
+
+// PrintDenoms prints the denom creations, metadata, mints and transfers
+func (d *Deployer) PrintDenoms(msgs []json.RawMessage) error {
+	for _, data := range msgs {
+		var msg struct {
+			Type      string       `json:"@type"`
+			Nonce     string       `json:"nonce"`
+			Amount    Funds        `json:"amount"`
+			Recipient string       `json:"recipient"`
+			Metadata  BankMetadata `json:"metadata"`
+			Token     Funds        `json:"token"`
+			Channel   string       `json:"source_channel"`
+			Receiver  string       `json:"receiver"`
+		}
+
+		err := json.Unmarshal(data, &msg)
+		if err != nil {
+			return d.error(err)
+		}
+
+		switch msg.Type {
+		case "/kujira.denom.MsgCreateDenom":
+			fmt.Printf("  create factory/%s/%s\n", d.address, msg.Nonce)
+		case "/kujira.denom.MsgSetDenomMetadata":
+			fmt.Printf(
+				"  set metadata of %s (%s)\n", msg.Metadata.Base, msg.Metadata.Symbol,
+			)
+		case "/ibc.applications.transfer.v1.MsgTransfer":
+			fmt.Printf(
+				"  transfer %s%s to %s via %s\n", msg.Token.Amount, msg.Token.Denom,
+				msg.Receiver, msg.Channel,
+			)
+		default:
+			fmt.Printf(
+				"  mint %s%s to %s\n", msg.Amount.Amount, msg.Amount.Denom,
+				msg.Recipient,
+			)
+		}
+	}
+
+	return nil
+}
+
+// PrintContracts prints what happens to every contract of a stage and the
+// rendered msgs
+func (d *Deployer) PrintContracts(contracts []Contract, msgs []json.RawMessage) {
+	types := map[string][]string{}
+
+	for _, data := range msgs {
+		var msg struct {
+			Type     string `json:"@type"`
+			Contract string `json:"contract"`
+		}
+
+		// actions of new contracts may target deployed contracts
+		err := json.Unmarshal(data, &msg)
+		if err != nil || msg.Type == "/cosmwasm.wasm.v1.MsgExecuteContract" {
+			continue
+		}
+
+		name := msg.Type[strings.LastIndex(msg.Type, ".")+1:]
+		types[msg.Contract] = append(types[msg.Contract], name)
+	}
+
+	for _, contract := range contracts {
+		address := d.Contracts[contract.Name].Address
+
+		_, deployed := d.labels[contract.Label]
+
+		action := "instantiate"
+		switch {
+		case contract.Allocate && !deployed:
+			action = "allocate"
+		case deployed && len(types[address]) == 0:
+			action = "skip"
+		case deployed:
+			action = strings.Join(types[address], ", ")
+		}
+
+		fmt.Printf("  %-12s %s %s (%s)\n", action, contract.Name, address,
+			contract.Label)
+	}
+
+	for _, data := range msgs {
+		formatted, err := json.MarshalIndent(data, "    ", "  ")
+		if err != nil {
+			formatted = data
+		}
+
+		fmt.Printf("    %s\n", formatted)
+	}
+}
+
+// simulate prints the gas used by the msgs, failures are expected for msgs
+// depending on previous stages
+func (d *Deployer) simulate(msgs []json.RawMessage) {
+	if len(msgs) == 0 {
+		return
+	}
+
+	gas, err := d.Simulate(msgs)
+	if err != nil {
+		fmt.Printf("  gas: simulation failed: %s\n", err)
+		return
+	}
+
+	fmt.Printf("  gas: %s\n", gas)
+}
+
+// Simulate returns the gas used by the msgs
+func (d *Deployer) Simulate(msgs []json.RawMessage) (string, error) {
+	signed, err := d.Sign(msgs)
+	if err != nil {
+		return "", err
+	}
+
+	output, err := d.node.Tx([]string{"encode", signed})
+	if err != nil {
+		return "", d.error(err)
+	}
+
+	api := d.node.Client()
+
+	return api.Simulate(strings.TrimSpace(string(output)))
+}
@@ -0,0 +1,252 @@
+package deployer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"pond/pond/registry"
+	"pond/utils"
+)
+
+// FetchCodes downloads all registry codes into the code cache and verifies
+// their checksums. Codes without a checksum get pinned to the downloaded one.
+func (d *Deployer) FetchCodes() error {
+	offline := d.Offline
+	d.Offline = false
+	defer func() { d.Offline = offline }()
+
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+
+	failed := []string{}
+	pinned := map[string]registry.Code{}
+
+	for name, code := range d.registry.Codes() {
+		wg.Add(1)
+		go func(name string, code registry.Code) {
+			defer wg.Done()
+
+			data, err := d.GetCode(code)
+
+			mtx.Lock()
+			defer mtx.Unlock()
+
+			if err != nil {
+				failed = append(failed, name)
+				return
+			}
+
+			if code.Checksum == "" {
+				code.Checksum = utils.Sha256(data)
+				pinned[name] = code
+			}
+
+			d.logger.Info().
+				Str("name", name).
+				Str("checksum", code.Checksum).
+				Msg("code verified")
+		}(name, code)
+	}
+
+	wg.Wait()
+
+	for name, code := range pinned {
+		err := d.registry.Set(name, code)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		err := fmt.Errorf("%d codes failed", len(failed))
+		d.logger.Err(err).Strs("codes", failed).Msg("")
+		return err
+	}
+
+	return nil
+}
+
+// UncachedCodes returns the names of all registry codes that can't be
+// loaded without network access
+func (d *Deployer) UncachedCodes() []string {
+	missing := []string{}
+
+	for name, code := range d.registry.Codes() {
+		if strings.HasPrefix(code.Source, "file://") {
+			continue
+		}
+
+		_, found := d.getCachedCode(code.Checksum)
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+
+	sort.Strings(missing)
+
+	return missing
+}
+
+// VerifyCodes loads every registry code from its source and checks its
+// checksum. Offline, the cached codes are checked instead. Registry codes
+// are compared with the hashes of their deployed code ids.
+func (d *Deployer) VerifyCodes() error {
+	deployed := map[string]string{}
+
+	err := d.UpdateDeployedCodes()
+	if err != nil {
+		d.logger.Warn().Msg("skip on-chain code hashes")
+	} else {
+		deployed = d.deployedCodeIds()
+	}
+
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+
+	results := map[string]string{}
+
+	for name, code := range d.registry.Codes() {
+		wg.Add(1)
+		go func(name string, code registry.Code) {
+			defer wg.Done()
+
+			var data []byte
+			var err error
+
+			if d.Offline {
+				data, err = d.GetCode(code)
+			} else {
+				data, err = d.loadCode(code)
+			}
+
+			result := "ok"
+			switch {
+			case err != nil:
+				result = "failed"
+			case code.Checksum == "":
+				result = "unpinned"
+			case d.checkCode(code, data) != nil:
+				result = "mismatch"
+			}
+
+			codeId, found := deployed[name]
+			if found && d.checkDeployedCode(name, code, codeId) != nil {
+				result = "mismatch"
+			}
+
+			mtx.Lock()
+			results[name] = result
+			mtx.Unlock()
+		}(name, code)
+	}
+
+	wg.Wait()
+
+	names := []string{}
+	padding := 0
+	for name := range results {
+		names = append(names, name)
+		if len(name) > padding {
+			padding = len(name)
+		}
+	}
+
+	sort.Strings(names)
+
+	failed := 0
+	for _, name := range names {
+		result := results[name]
+		if result == "failed" || result == "mismatch" {
+			failed++
+		}
+
+		codeId, found := deployed[name]
+		if !found {
+			codeId, found = d.CodeIds[name]
+		}
+		if !found {
+			codeId = "-"
+		}
+
+		fmt.Printf(
+			"%-8s %-*s %5s %s\n", result, padding, name, codeId,
+			d.registry.Codes()[name].Source,
+		)
+	}
+
+	if failed > 0 {
+		err := fmt.Errorf("%d codes failed verification", failed)
+		return d.error(err)
+	}
+
+	return nil
+}
+
+// deployedCodeIds returns the code ids registry codes are deployed as, by
+// their source on the chain in use or by the deployed plan contracts using
+// them
+func (d *Deployer) deployedCodeIds() map[string]string {
+	codeIds := map[string]string{}
+
+	for name, code := range d.registry.Codes() {
+		scheme, codeId, found := strings.Cut(code.Source, "://")
+		if found && scheme == d.chainId {
+			codeIds[name] = codeId
+		}
+	}
+
+	err := d.UseSender("")
+	if err == nil {
+		err = d.UpdateDeployedContracts()
+	}
+	if err != nil {
+		d.logger.Warn().Msg("skip deployed contracts")
+		return codeIds
+	}
+
+	for _, stage := range d.plan.Contracts {
+		for _, contract := range stage {
+			if d.getContractChain(contract) != d.chainId {
+				continue
+			}
+
+			info, found := d.labels[contract.Label]
+			if !found {
+				continue
+			}
+
+			_, found = codeIds[contract.Code]
+			if !found {
+				codeIds[contract.Code] = info.CodeId
+			}
+		}
+	}
+
+	return codeIds
+}
+
+// checkDeployedCode compares the registry checksum of a code with the hash
+// of its deployed code id
+func (d *Deployer) checkDeployedCode(
+	name string, code registry.Code, codeId string,
+) error {
+	for checksum, id := range d.codes {
+		if id != codeId || checksum == code.Checksum {
+			continue
+		}
+
+		err := fmt.Errorf("deployed code mismatch")
+		d.logger.Err(err).
+			Str("name", name).
+			Str("code_id", codeId).
+			Str("checksum", code.Checksum).
+			Str("deployed", checksum).
+			Msg("")
+		return err
+	}
+
+	return nil
+}
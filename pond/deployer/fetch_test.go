@@ -0,0 +1,45 @@
+package deployer
+
+import (
+	"testing"
+
+	"pond/pond/registry"
+)
+
+func TestCheckDeployedCode(t *testing.T) {
+	d := newCodeDeployer(t, nil)
+	d.codes = map[string]string{"AA": "1", "BB": "2"}
+
+	tests := []struct {
+		checksum string
+		codeId   string
+		fails    bool
+	}{
+		{"AA", "1", false},
+		{"BB", "1", true},
+		// unknown code ids aren't deployed yet
+		{"AA", "3", false},
+	}
+
+	for _, test := range tests {
+		err := d.checkDeployedCode("test", registry.Code{Checksum: test.checksum}, test.codeId)
+		if (err != nil) != test.fails {
+			t.Errorf("%s as code %s: got %v", test.checksum, test.codeId, err)
+		}
+	}
+}
+
+func TestDeployedCodeIds(t *testing.T) {
+	d := newCodeDeployer(t, nil)
+	d.registry = &registry.Registry{Data: map[string]registry.Code{
+		"local":  {Source: "kujira-1://4", Checksum: "AA"},
+		"remote": {Source: "kaiyo-1://5", Checksum: "BB"},
+		"file":   {Source: "file:///tmp/test.wasm", Checksum: "CC"},
+	}}
+
+	// without a sender only the sources are used
+	codeIds := d.deployedCodeIds()
+	if len(codeIds) != 1 || codeIds["local"] != "4" {
+		t.Errorf("got %v", codeIds)
+	}
+}
@@ -0,0 +1,184 @@
+package deployer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"pond/utils"
+
+	"github.com/rs/zerolog"
+	"gopkg.in/yaml.v3"
+)
+
+// ExpandFiles replaces directories by their wasm and plan files
+func ExpandFiles(logger zerolog.Logger, filenames []string) ([]string, error) {
+	files := []string{}
+
+	for _, filename := range filenames {
+		info, err := os.Stat(filename)
+		if err != nil {
+			logger.Err(err).Msg("")
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			files = append(files, filename)
+			continue
+		}
+
+		items, err := ListDir(logger, filename)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, items...)
+	}
+
+	return files, nil
+}
+
+// ListDir returns the wasm and plan files of a directory in deployment
+// order. Without a manifest, wasm files are deployed before plan files, both
+// sorted by name. Other json and yaml files, ex.: package.json, are skipped.
+func ListDir(logger zerolog.Logger, dir string) ([]string, error) {
+	logger.Debug().Str("dir", dir).Msg("list directory")
+
+	for _, name := range ManifestNames {
+		filename := filepath.Join(dir, name)
+
+		_, err := os.Stat(filename)
+		if err != nil {
+			continue
+		}
+
+		return LoadManifest(logger, filename)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		logger.Err(err).Msg("")
+		return nil, err
+	}
+
+	wasms := []string{}
+	plans := []string{}
+
+	// ReadDir returns the entries sorted by name
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		filename := filepath.Join(dir, entry.Name())
+
+		switch filepath.Ext(filename) {
+		case ".wasm":
+			wasms = append(wasms, filename)
+		case ".json", ".jsonc", ".yaml", ".yml":
+			if !isPlanFile(filename) {
+				logger.Debug().Str("file", filename).Msg("skip file")
+				continue
+			}
+
+			plans = append(plans, filename)
+		}
+	}
+
+	return append(wasms, plans...), nil
+}
+
+// LoadManifest returns the files listed in the manifest. Entries are
+// relative to the manifest and can be globs or directories.
+func LoadManifest(logger zerolog.Logger, filename string) ([]string, error) {
+	logger.Debug().Str("file", filename).Msg("load manifest")
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		logger.Err(err).Msg("")
+		return nil, err
+	}
+
+	var manifest Manifest
+
+	err = yaml.Unmarshal(data, &manifest)
+	if err != nil {
+		logger.Err(err).Str("file", filename).Msg("")
+		return nil, err
+	}
+
+	dir := filepath.Dir(filename)
+	files := []string{}
+
+	for _, entry := range manifest.Files {
+		matches, err := filepath.Glob(filepath.Join(dir, entry))
+		if err != nil {
+			logger.Err(err).Msg("")
+			return nil, err
+		}
+
+		if len(matches) == 0 {
+			err := fmt.Errorf("no file found")
+			logger.Err(err).Str("entry", entry).Msg("")
+			return nil, err
+		}
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				logger.Err(err).Msg("")
+				return nil, err
+			}
+
+			if !info.IsDir() {
+				files = append(files, match)
+				continue
+			}
+
+			items, err := ListDir(logger, match)
+			if err != nil {
+				return nil, err
+			}
+
+			files = append(files, items...)
+		}
+	}
+
+	return files, nil
+}
+
+// isPlanFile checks for plan keys, files that can't be parsed are kept so
+// loading them reports the error
+func isPlanFile(filename string) bool {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return true
+	}
+
+	switch filepath.Ext(filename) {
+	case ".yaml", ".yml":
+		content, err = utils.YamlToJson(content)
+		if err != nil {
+			return true
+		}
+	default:
+		content = utils.StripJsonComments(content)
+	}
+
+	var keys map[string]json.RawMessage
+
+	err = json.Unmarshal(content, &keys)
+	if err != nil {
+		return true
+	}
+
+	for _, key := range planKeys {
+		_, found := keys[key]
+		if found {
+			return true
+		}
+	}
+
+	return false
+}
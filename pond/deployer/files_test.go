@@ -0,0 +1,156 @@
+package deployer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// writeFiles creates the files relative to dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		filename := filepath.Join(dir, name)
+
+		err := os.MkdirAll(filepath.Dir(filename), 0o755)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = os.WriteFile(filename, []byte(content), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// relative strips dir from the filenames
+func relative(t *testing.T, dir string, filenames []string) []string {
+	t.Helper()
+
+	result := []string{}
+	for _, filename := range filenames {
+		name, err := filepath.Rel(dir, filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, filepath.ToSlash(name))
+	}
+	return result
+}
+
+func TestIsPlanFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{"plan.json", `{"contracts": []}`, true},
+		{"plan.jsonc", "{\n// codes\n\"codes\": {},\n}", true},
+		{"plan.yaml", "denoms:\n  - name: POND", true},
+		{"plan.yml", "sender: deployer", true},
+		{"package.json", `{"name": "contracts", "version": "1.0.0"}`, false},
+		{"tsconfig.json", `{"compilerOptions": {}}`, false},
+		{"pnpm-lock.yaml", "lockfileVersion: '6.0'", false},
+		// broken files are kept, so loading reports the error
+		{"broken.json", `{"contracts": [`, true},
+		{"list.json", `[1, 2]`, true},
+	}
+
+	dir := t.TempDir()
+
+	for _, test := range tests {
+		writeFiles(t, dir, map[string]string{test.name: test.content})
+
+		result := isPlanFile(filepath.Join(dir, test.name))
+		if result != test.expected {
+			t.Errorf("%s: got %v, want %v", test.name, result, test.expected)
+		}
+	}
+}
+
+func TestListDir(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, dir, map[string]string{
+		"b.wasm":        "",
+		"a.wasm":        "",
+		"2-plan.yaml":   "contracts: []",
+		"1-plan.json":   `{"codes": {}}`,
+		"package.json":  `{"name": "contracts"}`,
+		"README.md":     "",
+		"sub/c.wasm":    "",
+		"sub/plan.json": `{"contracts": []}`,
+	})
+
+	files, err := ListDir(zerolog.Nop(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"a.wasm", "b.wasm", "1-plan.json", "2-plan.yaml"}
+
+	result := relative(t, dir, files)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("got %v, want %v", result, expected)
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	writeFiles(t, dir, map[string]string{
+		"manifest.yaml": "files:\n  - plans/z.json\n  - wasm/*.wasm\n  - sub\n  - plans/a.json",
+		// a json manifest is ignored if there is a yaml one
+		"manifest.json":    `{"files": ["plans/a.json"]}`,
+		"wasm/b.wasm":      "",
+		"wasm/a.wasm":      "",
+		"plans/a.json":     `{"contracts": []}`,
+		"plans/z.json":     `{"contracts": []}`,
+		"sub/plan.json":    `{"contracts": []}`,
+		"sub/c.wasm":       "",
+		"sub/package.json": `{"name": "contracts"}`,
+	})
+
+	files, err := ListDir(zerolog.Nop(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// entries keep their order, globs and directories are sorted
+	expected := []string{
+		"plans/z.json", "wasm/a.wasm", "wasm/b.wasm",
+		"sub/c.wasm", "sub/plan.json", "plans/a.json",
+	}
+
+	result := relative(t, dir, files)
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("got %v, want %v", result, expected)
+	}
+}
+
+func TestLoadManifestErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+	}{
+		{"missing file", "files:\n  - missing.json"},
+		{"empty glob", "files:\n  - '*.wasm'"},
+		{"bad glob", "files:\n  - '[a'"},
+		{"invalid yaml", "files: [a"},
+	}
+
+	for _, test := range tests {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"manifest.yaml": test.manifest})
+
+		_, err := LoadManifest(zerolog.Nop(), filepath.Join(dir, "manifest.yaml"))
+		if err == nil {
+			t.Errorf("%s: no error", test.name)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package deployer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// intRegex matches rendered integers, ex.: "{{ .CodeIds.kujira_fin }} | int"
+var intRegex = regexp.MustCompile(`^(\d+)\s*\|\s*int\s*$`)
+
+// renderJSON executes the templates of all strings of a json document.
+// Strings are rendered separately, so results are escaped when encoding
+// and integers converted.
+func (d *Deployer) renderJSON(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+
+	err := decoder.Decode(&value)
+	if err != nil {
+		return nil, d.error(err)
+	}
+
+	value, err = d.renderValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = json.Marshal(value)
+	if err != nil {
+		return nil, d.error(err)
+	}
+
+	return data, nil
+}
+
+func (d *Deployer) renderValue(value any) (any, error) {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, item := range value {
+			rendered, err := d.renderValue(item)
+			if err != nil {
+				return nil, err
+			}
+			value[key] = rendered
+		}
+		return value, nil
+
+	case []any:
+		for i, item := range value {
+			rendered, err := d.renderValue(item)
+			if err != nil {
+				return nil, err
+			}
+			value[i] = rendered
+		}
+		return value, nil
+
+	case string:
+		if !strings.Contains(value, "{{") && !strings.Contains(value, "|") {
+			return value, nil
+		}
+
+		rendered, err := d.render(value)
+		if err != nil {
+			return nil, err
+		}
+
+		matches := intRegex.FindStringSubmatch(rendered)
+		if matches != nil {
+			return json.Number(matches[1]), nil
+		}
+
+		return rendered, nil
+	}
+
+	return value, nil
+}
+
+// render executes a plan template with the deployer as data
+func (d *Deployer) render(text string) (string, error) {
+	tmpl, err := template.New("").Funcs(d.funcs()).Parse(text)
+	if err != nil {
+		return "", d.error(err)
+	}
+
+	var buffer bytes.Buffer
+
+	err = tmpl.Execute(&buffer, d)
+	if err != nil {
+		return "", d.error(err)
+	}
+
+	return buffer.String(), nil
+}
+
+// funcs returns the functions available in plan templates
+func (d *Deployer) funcs() template.FuncMap {
+	return template.FuncMap{
+		// plan variables, set with --set key=value
+		"arg": func(key string, fallback ...string) (string, error) {
+			value, found := d.Vars[key]
+			if found {
+				return value, nil
+			}
+
+			if len(fallback) > 0 {
+				return fallback[0], nil
+			}
+
+			return "", fmt.Errorf("missing argument: --set %s=...", key)
+		},
+		"env": func(key string, fallback ...string) (string, error) {
+			value, found := os.LookupEnv(key)
+			if found {
+				return value, nil
+			}
+
+			if len(fallback) > 0 {
+				return fallback[0], nil
+			}
+
+			return "", fmt.Errorf("missing environment variable: %s", key)
+		},
+		// accepts account and contract names, accounts are resolved on the
+		// chain in use
+		"address": func(name string) (string, error) {
+			address, found := d.getAccount(name)
+			if found {
+				return address, nil
+			}
+
+			contract, found := d.Contracts[name]
+			if found {
+				return contract.Address, nil
+			}
+
+			return "", fmt.Errorf("address not found: %s", name)
+		},
+		"code": func(name string) (string, error) {
+			id, found := d.CodeIds[name]
+			if !found {
+				return "", fmt.Errorf("code not found: %s", name)
+			}
+
+			return id, nil
+		},
+		// transfer channel to another pond chain
+		"channel": func(chainId string) (string, error) {
+			channel, err := d.getChannel(chainId)
+			return channel.ChannelId, err
+		},
+		"now": func() Time {
+			return Time{time.Now()}
+		},
+		"blockTime": func() (Time, error) {
+			c := d.node.Client()
+			status, err := c.Status()
+			return Time{status.Time}, err
+		},
+		"duration": time.ParseDuration,
+		"add": func(a, b any) (string, error) {
+			return calc(a, b, new(big.Int).Add)
+		},
+		"sub": func(a, b any) (string, error) {
+			return calc(a, b, new(big.Int).Sub)
+		},
+		"mul": func(a, b any) (string, error) {
+			return calc(a, b, new(big.Int).Mul)
+		},
+		"div": func(a, b any) (string, error) {
+			return calc(a, b, func(x, y *big.Int) *big.Int {
+				if y.Sign() == 0 {
+					return nil
+				}
+				return new(big.Int).Quo(x, y)
+			})
+		},
+		// converts decimals to base units, ex.: scale "1.5" 6 is 1500000
+		"scale": func(value any, exponent int) (string, error) {
+			amount, ok := new(big.Rat).SetString(
+				strings.Replace(fmt.Sprint(value), "_", "", -1),
+			)
+			if !ok {
+				return "", fmt.Errorf("invalid number: %v", value)
+			}
+
+			factor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(exponent))), nil)
+			if exponent < 0 {
+				amount.Quo(amount, new(big.Rat).SetInt(factor))
+			} else {
+				amount.Mul(amount, new(big.Rat).SetInt(factor))
+			}
+
+			// fractions of base units are truncated
+			return new(big.Int).Quo(amount.Num(), amount.Denom()).String(), nil
+		},
+	}
+}
+
+// Time renders as RFC3339 in templates, Unix and UnixNano give timestamps
+type Time struct {
+	time.Time
+}
+
+func (t Time) String() string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Add keeps the result a Time, ex.: ((now).Add (duration "24h")).Unix
+func (t Time) Add(duration time.Duration) Time {
+	return Time{t.Time.Add(duration)}
+}
+
+// calc applies an integer operation to numbers given as strings or ints
+func calc(a, b any, fn func(x, y *big.Int) *big.Int) (string, error) {
+	x, ok := new(big.Int).SetString(strings.Replace(fmt.Sprint(a), "_", "", -1), 10)
+	if !ok {
+		return "", fmt.Errorf("invalid integer: %v", a)
+	}
+
+	y, ok := new(big.Int).SetString(strings.Replace(fmt.Sprint(b), "_", "", -1), 10)
+	if !ok {
+		return "", fmt.Errorf("invalid integer: %v", b)
+	}
+
+	result := fn(x, y)
+	if result == nil {
+		return "", fmt.Errorf("division by zero")
+	}
+
+	return result.String(), nil
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,685 @@
+package deployer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"pond/pond/templates"
+	"pond/utils"
+
+	"github.com/rs/zerolog"
+	"gopkg.in/yaml.v3"
+)
+
+// lintAddress is used as address of all contracts when rendering templates
+const lintAddress = "kujira1lint"
+
+var (
+	yamlLineRegex = regexp.MustCompile(`line (\d+)`)
+	intSuffix     = regexp.MustCompile(`\|\s*int\s*$`)
+)
+
+// Issue is a problem found in a plan file
+type Issue struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s:%d: %s", i.File, i.Line, i.Message)
+}
+
+// Linter checks plan files without a running chain. All files are added
+// first, so denoms and codes of every file are known, then contracts are
+// checked in deployment order.
+type Linter struct {
+	schema    *schema
+	codes     map[string]struct{}
+	denoms    map[string]Denom
+	contracts map[string]struct{}
+	files     []lintFile
+	issues    []Issue
+}
+
+type lintFile struct {
+	name string
+	root *yaml.Node
+}
+
+// schema is the subset of json schema used by the plan schema
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	AnyOf                []*schema          `json:"anyOf"`
+	Defs                 map[string]*schema `json:"$defs"`
+}
+
+// NewLinter creates a linter, codes are the names of the registry
+func NewLinter(codes []string) (*Linter, error) {
+	data, err := templates.Templates.ReadFile("schema/plan.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var root schema
+
+	err = json.Unmarshal(data, &root)
+	if err != nil {
+		return nil, err
+	}
+
+	linter := Linter{
+		schema:    &root,
+		codes:     map[string]struct{}{},
+		denoms:    map[string]Denom{},
+		contracts: map[string]struct{}{},
+	}
+
+	for _, code := range codes {
+		linter.codes[code] = struct{}{}
+	}
+
+	return &linter, nil
+}
+
+// Add parses and validates a plan file and collects its denoms and codes
+func (l *Linter) Add(filename string, data []byte) {
+	switch filepath.Ext(filename) {
+	case ".yaml", ".yml":
+	default:
+		// tabs can't be part of json strings, but yaml rejects them
+		data = utils.StripJsonComments(data)
+		data = bytes.ReplaceAll(data, []byte("\t"), []byte(" "))
+	}
+
+	var document yaml.Node
+
+	err := yaml.Unmarshal(data, &document)
+	if err != nil {
+		line := 0
+		matches := yamlLineRegex.FindStringSubmatch(err.Error())
+		if matches != nil {
+			line, _ = strconv.Atoi(matches[1])
+		}
+
+		message := strings.TrimPrefix(err.Error(), "yaml: ")
+		l.issue(filename, line, "invalid syntax: %s", message)
+		return
+	}
+
+	if len(document.Content) == 0 {
+		l.issue(filename, 1, "empty plan")
+		return
+	}
+
+	root := document.Content[0]
+
+	// the structure is required for all further checks
+	if !l.validate(filename, l.schema, root) {
+		return
+	}
+
+	for _, denom := range items(lookup(root, "denoms")) {
+		l.denoms[value(denom, "name")] = Denom{
+			Name:  value(denom, "name"),
+			Path:  value(denom, "path"),
+			Nonce: value(denom, "nonce"),
+		}
+	}
+
+	codes := lookup(root, "codes")
+	if codes != nil {
+		for i := 0; i < len(codes.Content); i += 2 {
+			l.codes[codes.Content[i].Value] = struct{}{}
+		}
+	}
+
+	l.files = append(l.files, lintFile{name: filename, root: root})
+}
+
+// Lint checks all contracts in deployment order and returns all issues
+func (l *Linter) Lint() []Issue {
+	for _, file := range l.files {
+		stages := items(lookup(file.root, "contracts"))
+
+		// flat lists are ordered by their references on deployment
+		if len(stages) > 0 && isContract(stages[0]) {
+			for _, contract := range stages {
+				l.contracts[value(contract, "name")] = struct{}{}
+
+				for _, denom := range items(lookup(contract, "creates")) {
+					l.denoms[value(denom, "name")] = Denom{
+						Name:  value(denom, "name"),
+						Nonce: value(denom, "nonce"),
+					}
+				}
+			}
+
+			stages = []*yaml.Node{{Kind: yaml.SequenceNode, Content: stages}}
+		}
+
+		for _, stage := range stages {
+			created := []Denom{}
+
+			// stage objects hold their contracts with a chain
+			if stage.Kind == yaml.MappingNode {
+				stage = lookup(stage, "contracts")
+			}
+
+			for _, contract := range items(stage) {
+				l.lintContract(file.name, contract)
+
+				for _, denom := range items(lookup(contract, "creates")) {
+					created = append(created, Denom{
+						Name:  value(denom, "name"),
+						Nonce: value(denom, "nonce"),
+					})
+				}
+			}
+
+			// created denoms are available after the stage
+			for _, denom := range created {
+				l.denoms[denom.Name] = denom
+			}
+		}
+	}
+
+	order := map[string]int{}
+	for _, issue := range l.issues {
+		_, found := order[issue.File]
+		if !found {
+			order[issue.File] = len(order)
+		}
+	}
+
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		a, b := l.issues[i], l.issues[j]
+		if a.File != b.File {
+			return order[a.File] < order[b.File]
+		}
+		return a.Line < b.Line
+	})
+
+	return l.issues
+}
+
+func (l *Linter) lintContract(file string, contract *yaml.Node) {
+	// the address is known before instantiation, contracts of the same
+	// stage are instantiated in order
+	l.contracts[value(contract, "name")] = struct{}{}
+
+	code := lookup(contract, "code")
+	if code != nil {
+		_, found := l.codes[code.Value]
+		if !found {
+			l.issue(file, code.Line, "code not found in registry: %s", code.Value)
+		}
+	}
+
+	funds := lookup(contract, "funds")
+	if funds != nil {
+		l.lintFunds(file, funds)
+	}
+
+	l.lintMsg(file, lookup(contract, "msg"))
+	l.lintMsg(file, lookup(contract, "migrate"))
+
+	for _, action := range items(lookup(contract, "actions")) {
+		l.lintMsg(file, lookup(action, "contract"))
+		l.lintMsg(file, lookup(action, "msg"))
+
+		funds := lookup(action, "funds")
+		if funds != nil {
+			l.lintFunds(file, funds)
+		}
+	}
+
+}
+
+func (l *Linter) lintFunds(file string, node *yaml.Node) {
+	_, ok := l.lintTemplate(file, node)
+	if !ok {
+		return
+	}
+
+	_, err := l.deployer().StringToFunds(node.Value)
+	if err != nil {
+		l.issue(file, node.Line, "invalid funds: %s", node.Value)
+	}
+}
+
+// lintMsg checks all string values of a msg
+func (l *Linter) lintMsg(file string, node *yaml.Node) {
+	if node == nil {
+		return
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			l.lintMsg(file, node.Content[i])
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			l.lintMsg(file, item)
+		}
+	case yaml.ScalarNode:
+		if node.Tag != "!!str" {
+			return
+		}
+
+		rendered, ok := l.lintTemplate(file, node)
+		if !ok {
+			return
+		}
+
+		if intSuffix.MatchString(rendered) && !intRegex.MatchString(rendered) {
+			l.issue(file, node.Line, "invalid int conversion: %s", node.Value)
+		}
+	}
+}
+
+// lintTemplate checks the references of a template and renders it
+func (l *Linter) lintTemplate(file string, node *yaml.Node) (string, bool) {
+	if !strings.Contains(node.Value, "{{") {
+		return node.Value, true
+	}
+
+	d := l.deployer()
+
+	tmpl, err := template.New("").Funcs(l.funcs(d)).Parse(node.Value)
+	if err != nil {
+		message := strings.TrimPrefix(err.Error(), "template: :1: ")
+		l.issue(file, node.Line, "invalid template: %s", message)
+		return "", false
+	}
+
+	valid := true
+
+	walk(tmpl.Tree.Root, func(ident []string) {
+		message := l.checkReference(ident)
+		if message != "" {
+			l.issue(file, node.Line, "%s", message)
+			valid = false
+		}
+	})
+
+	if !valid {
+		return "", false
+	}
+
+	var buffer bytes.Buffer
+
+	err = tmpl.Execute(&buffer, d)
+	if err != nil {
+		l.issue(file, node.Line, "invalid template: %s", err)
+		return "", false
+	}
+
+	return buffer.String(), true
+}
+
+// checkReference returns a message if a field chain like
+// .Denoms.USK.Path can't be resolved
+func (l *Linter) checkReference(ident []string) string {
+	reference := "." + strings.Join(ident, ".")
+
+	var (
+		found     bool
+		fieldType reflect.Type
+	)
+
+	switch ident[0] {
+	case "Denoms":
+		if len(ident) < 2 {
+			return ""
+		}
+		_, found = l.denoms[ident[1]]
+		fieldType = reflect.TypeOf(Denom{})
+	case "Contracts":
+		if len(ident) < 2 {
+			return ""
+		}
+		_, found = l.contracts[ident[1]]
+		fieldType = reflect.TypeOf(Contract{})
+	case "CodeIds":
+		if len(ident) < 2 {
+			return ""
+		}
+		_, found = l.codes[ident[1]]
+	default:
+		deployer := reflect.TypeOf(&Deployer{})
+		_, method := deployer.MethodByName(ident[0])
+		_, field := deployer.Elem().FieldByName(ident[0])
+		if !method && !field {
+			return fmt.Sprintf("unknown reference: %s", reference)
+		}
+		return ""
+	}
+
+	if !found {
+		return fmt.Sprintf(
+			"%s not defined in an earlier stage: %s",
+			strings.ToLower(strings.TrimSuffix(ident[0], "s")), reference,
+		)
+	}
+
+	if len(ident) > 2 && fieldType != nil {
+		_, found := fieldType.FieldByName(ident[2])
+		if !found {
+			return fmt.Sprintf("unknown field: %s", reference)
+		}
+	}
+
+	return ""
+}
+
+// funcs returns the template functions of the deployer, functions needing a
+// running chain or deploy arguments return placeholders
+func (l *Linter) funcs(d *Deployer) template.FuncMap {
+	funcs := d.funcs()
+
+	funcs["arg"] = func(key string, fallback ...string) string {
+		if len(fallback) > 0 {
+			return fallback[0]
+		}
+		return "0"
+	}
+	funcs["address"] = func(name string) string {
+		return lintAddress
+	}
+	funcs["code"] = func(name string) (string, error) {
+		_, found := l.codes[name]
+		if !found {
+			return "", fmt.Errorf("code not found: %s", name)
+		}
+		return "1", nil
+	}
+	funcs["channel"] = func(chainId string) string {
+		return "channel-0"
+	}
+	funcs["blockTime"] = funcs["now"]
+
+	return funcs
+}
+
+// deployer returns a deployer with placeholders for all known items, used
+// to render templates
+func (l *Linter) deployer() *Deployer {
+	d := Deployer{
+		logger:    zerolog.Nop(),
+		Denoms:    map[string]Denom{},
+		Contracts: map[string]Contract{},
+		CodeIds:   map[string]string{},
+		Vars:      map[string]string{},
+	}
+
+	for name, denom := range l.denoms {
+		if denom.Path == "" {
+			denom.Path = fmt.Sprintf("factory/%s/%s", lintAddress, denom.Nonce)
+		}
+
+		denom.IbcPath = map[string]string{}
+		for _, transfer := range denom.Transfers {
+			denom.IbcPath[transfer.Chain] = "ibc/" + strings.Repeat("0", 64)
+		}
+
+		d.Denoms[name] = denom
+	}
+
+	for name := range l.contracts {
+		d.Contracts[name] = Contract{Name: name, Address: lintAddress}
+	}
+
+	for name := range l.codes {
+		d.CodeIds[name] = "1"
+	}
+
+	return &d
+}
+
+// validate checks the node against the schema, it returns false on type
+// mismatches
+func (l *Linter) validate(file string, s *schema, node *yaml.Node) bool {
+	if s.Ref != "" {
+		s = l.schema.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+	}
+
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	actual := nodeType(node)
+
+	// the first alternative matching the type is used, objects prefer the
+	// first one with all required fields, ex.: stages and contracts
+	var match *schema
+
+	for _, alternative := range s.AnyOf {
+		if alternative.Ref != "" {
+			alternative = l.schema.Defs[strings.TrimPrefix(alternative.Ref, "#/$defs/")]
+		}
+
+		if alternative.Type != actual {
+			continue
+		}
+
+		if match == nil {
+			match = alternative
+		}
+
+		if actual == "object" && hasFields(node, alternative.Required) {
+			match = alternative
+			break
+		}
+	}
+
+	if match != nil {
+		return l.validate(file, match, node)
+	}
+
+	if len(s.AnyOf) > 0 {
+		l.issue(file, node.Line, "unexpected %s", actual)
+		return false
+	}
+
+	if s.Type != "" && s.Type != actual &&
+		!(s.Type == "number" && actual == "integer") {
+		l.issue(file, node.Line, "expected %s, got %s", s.Type, actual)
+		return false
+	}
+
+	valid := true
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		keys := map[string]struct{}{}
+
+		for i := 0; i < len(node.Content); i += 2 {
+			key := node.Content[i]
+			keys[key.Value] = struct{}{}
+
+			property, found := s.Properties[key.Value]
+			if found {
+				valid = l.validate(file, property, node.Content[i+1]) && valid
+				continue
+			}
+
+			additional := strings.TrimSpace(string(s.AdditionalProperties))
+
+			if additional == "false" {
+				l.issue(file, key.Line, "unknown field: %s", key.Value)
+				continue
+			}
+
+			if strings.HasPrefix(additional, "{") {
+				var property schema
+				json.Unmarshal(s.AdditionalProperties, &property)
+				valid = l.validate(file, &property, node.Content[i+1]) && valid
+			}
+		}
+
+		for _, name := range s.Required {
+			_, found := keys[name]
+			if !found {
+				l.issue(file, node.Line, "missing field: %s", name)
+			}
+		}
+
+	case yaml.SequenceNode:
+		if s.Items == nil {
+			return valid
+		}
+
+		for _, item := range node.Content {
+			valid = l.validate(file, s.Items, item) && valid
+		}
+	}
+
+	return valid
+}
+
+// isContract tells contracts of flat lists from stage objects
+func isContract(node *yaml.Node) bool {
+	return node.Kind == yaml.MappingNode && lookup(node, "contracts") == nil
+}
+
+// hasFields checks a mapping for all given keys
+func hasFields(node *yaml.Node, fields []string) bool {
+	for _, field := range fields {
+		if lookup(node, field) == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (l *Linter) issue(file string, line int, format string, args ...any) {
+	l.issues = append(l.issues, Issue{
+		File:    file,
+		Line:    line,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// walk calls fn for every field chain of the template, ex.: .Denoms.USK.Path
+func walk(node parse.Node, fn func([]string)) {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+		for _, item := range node.Nodes {
+			walk(item, fn)
+		}
+	case *parse.ActionNode:
+		walk(node.Pipe, fn)
+	case *parse.IfNode:
+		walk(&node.BranchNode, fn)
+	case *parse.RangeNode:
+		walk(&node.BranchNode, fn)
+	case *parse.WithNode:
+		walk(&node.BranchNode, fn)
+	case *parse.BranchNode:
+		walk(node.Pipe, fn)
+		walk(node.List, fn)
+		walk(node.ElseList, fn)
+	case *parse.PipeNode:
+		if node == nil {
+			return
+		}
+		for _, cmd := range node.Cmds {
+			walk(cmd, fn)
+		}
+	case *parse.ChainNode:
+		walk(node.Node, fn)
+	case *parse.CommandNode:
+		// index .Denoms "USK"
+		if len(node.Args) == 3 && node.Args[0].String() == "index" {
+			field, isField := node.Args[1].(*parse.FieldNode)
+			key, isString := node.Args[2].(*parse.StringNode)
+			if isField && isString {
+				fn(append(append([]string{}, field.Ident...), key.Text))
+				return
+			}
+		}
+		for _, arg := range node.Args {
+			walk(arg, fn)
+		}
+	case *parse.FieldNode:
+		fn(node.Ident)
+	case *parse.VariableNode:
+		if len(node.Ident) > 1 && node.Ident[0] == "$" {
+			fn(node.Ident[1:])
+		}
+	}
+}
+
+func nodeType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+
+	switch node.Tag {
+	case "!!str":
+		return "string"
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	case "!!bool":
+		return "boolean"
+	case "!!null":
+		return "null"
+	}
+
+	return node.Tag
+}
+
+// lookup returns the value of a mapping key
+func lookup(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func value(node *yaml.Node, key string) string {
+	item := lookup(node, key)
+	if item == nil {
+		return ""
+	}
+	return item.Value
+}
+
+func items(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return node.Content
+}
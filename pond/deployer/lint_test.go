@@ -0,0 +1,180 @@
+package deployer
+
+import (
+	"reflect"
+	"testing"
+)
+
+// lint runs the linter on the given files, in order
+func lint(t *testing.T, codes []string, files ...[2]string) []string {
+	t.Helper()
+
+	linter, err := NewLinter(codes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		linter.Add(file[0], []byte(file[1]))
+	}
+
+	issues := []string{}
+	for _, issue := range linter.Lint() {
+		issues = append(issues, issue.String())
+	}
+
+	return issues
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		plan     string
+		expected []string
+	}{
+		{
+			name: "valid",
+			plan: `
+denoms:
+  - name: USK
+    path: factory/kujira1/uusk
+contracts:
+  - - name: oracle
+      code: kujira_oracle
+      label: oracle
+      msg: {}
+  - - name: market
+      code: kujira_fin
+      label: market
+      funds: 1000{{ .Denoms.USK.Path }}
+      msg:
+        oracle: "{{ .Contracts.oracle.Address }}"
+        owner: '{{ address "deployer" }}'
+        code: '{{ code "kujira_oracle" }}'
+`,
+			expected: []string{},
+		},
+		{
+			name: "unknown template function",
+			plan: `
+contracts:
+  - name: market
+    code: kujira_fin
+    label: market
+    msg:
+      owner: "{{ owner }}"
+`,
+			expected: []string{
+				`plan.yaml:7: invalid template: function "owner" not defined`,
+			},
+		},
+		{
+			name: "unknown contract",
+			plan: `
+contracts:
+  - - name: market
+      code: kujira_fin
+      label: market
+      msg:
+        oracle: "{{ .Contracts.oracle.Address }}"
+`,
+			expected: []string{
+				"plan.yaml:7: contract not defined in an earlier stage: .Contracts.oracle.Address",
+			},
+		},
+		{
+			name: "contract of a later stage",
+			plan: `
+contracts:
+  - - name: market
+      code: kujira_fin
+      label: market
+      msg:
+        oracle: '{{ (index .Contracts "oracle").Address }}'
+  - - name: oracle
+      code: kujira_oracle
+      label: oracle
+`,
+			expected: []string{
+				"plan.yaml:7: contract not defined in an earlier stage: .Contracts.oracle",
+			},
+		},
+		{
+			name: "unknown denom",
+			plan: `
+contracts:
+  - name: market
+    code: kujira_fin
+    label: market
+    funds: "1000{{ .Denoms.USK.Path }}"
+`,
+			expected: []string{
+				"plan.yaml:6: denom not defined in an earlier stage: .Denoms.USK.Path",
+			},
+		},
+		{
+			name: "unknown field",
+			plan: `
+denoms:
+  - name: USK
+contracts:
+  - name: market
+    code: kujira_fin
+    label: market
+    msg:
+      denom: "{{ .Denoms.USK.Denom }}"
+`,
+			expected: []string{
+				"plan.yaml:9: unknown field: .Denoms.USK.Denom",
+			},
+		},
+		{
+			name: "missing code",
+			plan: `
+contracts:
+  - name: market
+    code: kujira_bow
+    label: market
+    msg:
+      code: '{{ code "kujira_orca" }}'
+`,
+			expected: []string{
+				"plan.yaml:4: code not found in registry: kujira_bow",
+				`plan.yaml:7: invalid template: template: :1:3: executing "" at <code "kujira_orca">: error calling code: code not found: kujira_orca`,
+			},
+		},
+	}
+
+	codes := []string{"kujira_fin", "kujira_oracle"}
+
+	for _, test := range tests {
+		issues := lint(t, codes, [2]string{"plan.yaml", test.plan})
+		if !reflect.DeepEqual(issues, test.expected) {
+			t.Errorf("%s: got %q, want %q", test.name, issues, test.expected)
+		}
+	}
+}
+
+func TestLintFiles(t *testing.T) {
+	// codes and denoms of all files are known, issues are sorted by file
+	issues := lint(t, nil,
+		[2]string{"b.jsonc", `{
+			// market
+			"contracts": [{
+				"name": "market",
+				"code": "kujira_fin",
+				"label": "market",
+				"msg": {"denom": "{{ .Denoms.USK.Path }}", "oracle": "{{ .Contracts.oracle.Address }}"},
+			}],
+		}`},
+		[2]string{"a.yaml", "codes:\n  kujira_fin: file://fin.wasm\ndenoms:\n  - name: USK\n"},
+	)
+
+	expected := []string{
+		"b.jsonc:7: contract not defined in an earlier stage: .Contracts.oracle.Address",
+	}
+
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("got %q, want %q", issues, expected)
+	}
+}
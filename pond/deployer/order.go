@@ -0,0 +1,301 @@
+package deployer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// OrderContracts puts a flat list of contracts into stages, so every
+// contract is instantiated after the contracts it references. References
+// to denoms created by a contract require its instantiation. References to
+// contract addresses only need the address, so cycles of those are broken
+// by allocating the address in the first stage.
+func OrderContracts(contracts []Contract) ([][]Contract, error) {
+	creators := map[string]string{}
+	names := map[string]struct{}{}
+
+	for _, contract := range contracts {
+		names[contract.Name] = struct{}{}
+		for _, denom := range contract.Creates {
+			creators[denom.Name] = contract.Name
+		}
+	}
+
+	// dependencies of every contract, true for hard dependencies
+	deps := map[string]map[string]bool{}
+
+	depend := func(name, dep string, hard bool) {
+		_, found := names[dep]
+		if found && dep != name {
+			deps[name][dep] = deps[name][dep] || hard
+		}
+	}
+
+	for _, contract := range contracts {
+		deps[contract.Name] = map[string]bool{}
+
+		refs, err := contractRefs(contract)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range refs.contracts {
+			depend(contract.Name, name, false)
+		}
+
+		depend(contract.Name, contract.Admin, false)
+
+		for _, denom := range refs.denoms {
+			creator, found := creators[denom]
+			if !found {
+				continue
+			}
+
+			if creator == contract.Name {
+				err := fmt.Errorf(
+					"%s references denom %s created by itself", contract.Name, denom,
+				)
+				return nil, err
+			}
+
+			depend(contract.Name, creator, true)
+		}
+	}
+
+	placed := map[string]struct{}{}
+	allocated := []Contract{}
+	stages := [][]Contract{}
+
+	for len(placed) < len(contracts) {
+		stage := []Contract{}
+
+		for _, contract := range contracts {
+			_, done := placed[contract.Name]
+			if done {
+				continue
+			}
+
+			ready := true
+			for dep := range deps[contract.Name] {
+				_, done := placed[dep]
+				if !done {
+					ready = false
+					break
+				}
+			}
+
+			if ready {
+				stage = append(stage, contract)
+			}
+		}
+
+		if len(stage) > 0 {
+			for _, contract := range stage {
+				placed[contract.Name] = struct{}{}
+			}
+			stages = append(stages, stage)
+			continue
+		}
+
+		// cycle: allocate the first contract only referenced by address
+		contract, found := allocatable(contracts, placed, deps)
+		if !found {
+			remaining := []string{}
+			for _, contract := range contracts {
+				_, done := placed[contract.Name]
+				if !done {
+					remaining = append(remaining, contract.Name)
+				}
+			}
+
+			err := fmt.Errorf(
+				"dependency cycle between: %s", strings.Join(remaining, ", "),
+			)
+			return nil, err
+		}
+
+		contract.Allocate = true
+		allocated = append(allocated, contract)
+
+		for _, dependencies := range deps {
+			delete(dependencies, contract.Name)
+		}
+	}
+
+	if len(allocated) > 0 {
+		if len(stages) == 0 {
+			stages = append(stages, []Contract{})
+		}
+		stages[0] = append(allocated, stages[0]...)
+	}
+
+	return stages, nil
+}
+
+// allocatable returns the first remaining contract that is only referenced
+// by address
+func allocatable(
+	contracts []Contract,
+	placed map[string]struct{},
+	deps map[string]map[string]bool,
+) (Contract, bool) {
+	for _, contract := range contracts {
+		_, done := placed[contract.Name]
+		if done {
+			continue
+		}
+
+		dependents := 0
+		hard := false
+
+		for name, dependencies := range deps {
+			_, done := placed[name]
+			if done {
+				continue
+			}
+
+			hardDep, found := dependencies[contract.Name]
+			if found {
+				dependents++
+				hard = hard || hardDep
+			}
+		}
+
+		if dependents > 0 && !hard {
+			return contract, true
+		}
+	}
+
+	return Contract{}, false
+}
+
+type references struct {
+	contracts []string
+	denoms    []string
+}
+
+// contractRefs returns the contracts and denoms the templates of a contract
+// refer to, by fields like .Contracts.X or by address "X"
+func contractRefs(contract Contract) (references, error) {
+	refs := references{}
+
+	data, err := json.Marshal([]any{
+		contract.Msg, contract.Funds, contract.Actions, contract.Migrate,
+	})
+	if err != nil {
+		return refs, err
+	}
+
+	var value any
+
+	err = json.Unmarshal(data, &value)
+	if err != nil {
+		return refs, err
+	}
+
+	funcs := (&Deployer{}).funcs()
+
+	for _, text := range templateStrings(value) {
+		// invalid templates are reported when rendering
+		tmpl, err := template.New("").Funcs(funcs).Parse(text)
+		if err != nil {
+			continue
+		}
+
+		walk(tmpl.Tree.Root, func(ident []string) {
+			if len(ident) < 2 {
+				return
+			}
+
+			switch ident[0] {
+			case "Contracts":
+				refs.contracts = append(refs.contracts, ident[1])
+			case "Denoms":
+				refs.denoms = append(refs.denoms, ident[1])
+			}
+		})
+
+		walkCalls(tmpl.Tree.Root, func(name string, args []parse.Node) {
+			if name != "address" || len(args) != 1 {
+				return
+			}
+
+			arg, isString := args[0].(*parse.StringNode)
+			if isString {
+				refs.contracts = append(refs.contracts, arg.Text)
+			}
+		})
+	}
+
+	return refs, nil
+}
+
+// templateStrings returns all strings of a json value containing templates
+func templateStrings(value any) []string {
+	switch value := value.(type) {
+	case map[string]any:
+		texts := []string{}
+		for _, item := range value {
+			texts = append(texts, templateStrings(item)...)
+		}
+		return texts
+	case []any:
+		texts := []string{}
+		for _, item := range value {
+			texts = append(texts, templateStrings(item)...)
+		}
+		return texts
+	case string:
+		if strings.Contains(value, "{{") {
+			return []string{value}
+		}
+	}
+
+	return nil
+}
+
+// walkCalls calls fn for every function call of the template with its
+// arguments, ex.: address "kujira_fin_kuji_usk"
+func walkCalls(node parse.Node, fn func(string, []parse.Node)) {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+		for _, item := range node.Nodes {
+			walkCalls(item, fn)
+		}
+	case *parse.ActionNode:
+		walkCalls(node.Pipe, fn)
+	case *parse.IfNode:
+		walkCalls(&node.BranchNode, fn)
+	case *parse.RangeNode:
+		walkCalls(&node.BranchNode, fn)
+	case *parse.WithNode:
+		walkCalls(&node.BranchNode, fn)
+	case *parse.BranchNode:
+		walkCalls(node.Pipe, fn)
+		walkCalls(node.List, fn)
+		walkCalls(node.ElseList, fn)
+	case *parse.PipeNode:
+		if node == nil {
+			return
+		}
+		for _, cmd := range node.Cmds {
+			walkCalls(cmd, fn)
+		}
+	case *parse.ChainNode:
+		walkCalls(node.Node, fn)
+	case *parse.CommandNode:
+		identifier, isIdentifier := node.Args[0].(*parse.IdentifierNode)
+		if isIdentifier {
+			fn(identifier.Ident, node.Args[1:])
+		}
+		for _, arg := range node.Args {
+			walkCalls(arg, fn)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package deployer
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func contract(name, msg string) Contract {
+	var fields map[string]json.RawMessage
+
+	err := json.Unmarshal([]byte(msg), &fields)
+	if err != nil {
+		panic(err)
+	}
+
+	return Contract{Name: name, Msg: fields}
+}
+
+// stageNames returns the contract names of all stages, allocated contracts
+// are suffixed with "*"
+func stageNames(stages [][]Contract) [][]string {
+	names := [][]string{}
+	for _, stage := range stages {
+		items := []string{}
+		for _, contract := range stage {
+			name := contract.Name
+			if contract.Allocate {
+				name += "*"
+			}
+			items = append(items, name)
+		}
+		names = append(names, items)
+	}
+	return names
+}
+
+func TestOrderContracts(t *testing.T) {
+	tests := []struct {
+		name      string
+		contracts []Contract
+		expected  [][]string
+	}{
+		{
+			name: "field reference",
+			contracts: []Contract{
+				contract("market", `{"oracle": "{{ .Contracts.oracle.Address }}"}`),
+				contract("oracle", `{}`),
+			},
+			expected: [][]string{{"oracle"}, {"market"}},
+		},
+		{
+			name: "address function",
+			contracts: []Contract{
+				contract("market", `{"oracle": "{{ address \"oracle\" }}"}`),
+				contract("oracle", `{}`),
+			},
+			expected: [][]string{{"oracle"}, {"market"}},
+		},
+		{
+			name: "index and nested pipeline",
+			contracts: []Contract{
+				contract("c", `{"b": "{{ (index .Contracts \"b\").Address }}"}`),
+				contract("b", `{"a": "{{ printf \"%s\" (address \"a\") }}"}`),
+				contract("a", `{}`),
+			},
+			expected: [][]string{{"a"}, {"b"}, {"c"}},
+		},
+		{
+			name: "accounts and codes are no dependencies",
+			contracts: []Contract{
+				contract("market", `{"owner": "{{ address \"deployer\" }}", "code": "{{ code \"oracle\" }}"}`),
+				contract("oracle", `{}`),
+			},
+			expected: [][]string{{"market", "oracle"}},
+		},
+		{
+			name: "admin",
+			contracts: []Contract{
+				{Name: "market", Admin: "dao"},
+				{Name: "dao"},
+			},
+			expected: [][]string{{"dao"}, {"market"}},
+		},
+		{
+			name: "denom created by a contract",
+			contracts: []Contract{
+				contract("market", `{"denom": "{{ .Denoms.POND.Path }}"}`),
+				{Name: "minter", Creates: []Denom{{Name: "POND"}}},
+			},
+			expected: [][]string{{"minter"}, {"market"}},
+		},
+		{
+			name: "address cycle is allocated",
+			contracts: []Contract{
+				contract("market", `{"orca": "{{ .Contracts.orca.Address }}"}`),
+				contract("orca", `{"market": "{{ address \"market\" }}"}`),
+			},
+			expected: [][]string{{"market*", "orca"}, {"market"}},
+		},
+	}
+
+	for _, test := range tests {
+		stages, err := OrderContracts(test.contracts)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		result := stageNames(stages)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("%s: got %v, want %v", test.name, result, test.expected)
+		}
+	}
+}
+
+func TestOrderContractsErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		contracts []Contract
+		expected  string
+	}{
+		{
+			name: "denom cycle",
+			contracts: []Contract{
+				{
+					Name:    "a",
+					Creates: []Denom{{Name: "A"}},
+					Msg:     contract("", `{"denom": "{{ .Denoms.B.Path }}"}`).Msg,
+				},
+				{
+					Name:    "b",
+					Creates: []Denom{{Name: "B"}},
+					Msg:     contract("", `{"denom": "{{ .Denoms.A.Path }}"}`).Msg,
+				},
+			},
+			expected: "dependency cycle between: a, b",
+		},
+		{
+			name: "own denom",
+			contracts: []Contract{
+				{
+					Name:    "a",
+					Creates: []Denom{{Name: "A"}},
+					Msg:     contract("", `{"denom": "{{ .Denoms.A.Path }}"}`).Msg,
+				},
+			},
+			expected: "a references denom A created by itself",
+		},
+	}
+
+	for _, test := range tests {
+		_, err := OrderContracts(test.contracts)
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("%s: got %v, want %s", test.name, err, test.expected)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package deployer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"pond/pond/client"
+)
+
+// UseSender sets the sender of the deployment, --from takes precedence over
+// the plan sender, the default is the deployer account
+func (d *Deployer) UseSender(name string) error {
+	if d.From != "" {
+		name = d.From
+	}
+
+	if name == "" {
+		name = "deployer"
+	}
+
+	return d.SetSender(name)
+}
+
+// SetSender sets the account that signs all deployment txs. Contracts and
+// factory denoms are looked up by their sender, so the caches are reset.
+// Accounts are only known after init, so the sender is resolved on first use.
+func (d *Deployer) SetSender(name string) error {
+	if name == d.from {
+		return nil
+	}
+
+	address, found := d.getAccount(name)
+	if !found {
+		err := fmt.Errorf("account not found")
+		d.logger.Err(err).Str("sender", name).Msg("")
+		return err
+	}
+
+	d.logger.Info().Str("sender", name).Msg("set sender")
+
+	// denoms with a path set in the plan are kept
+	for symbol, denom := range d.Denoms {
+		if strings.HasPrefix(denom.Path, "factory/"+d.address+"/") {
+			delete(d.Denoms, symbol)
+		}
+	}
+
+	d.from = name
+	d.address = address
+	d.addresses = map[string]struct{}{}
+	d.labels = map[string]client.ContractInfo{}
+
+	for chainId, target := range d.chains {
+		target.address = d.getChainAccounts(chainId)[name]
+		target.addresses = map[string]struct{}{}
+		target.labels = map[string]client.ContractInfo{}
+	}
+
+	return nil
+}
+
+// GetPermission returns the instantiate permission of a code, nil keeps the
+// chain default
+func (d *Deployer) GetPermission(code string) (*AccessConfig, error) {
+	permission, found := d.plan.Permissions[code]
+	if !found {
+		return nil, nil
+	}
+
+	switch permission.Type {
+	case "everybody":
+		return &AccessConfig{Permission: "ACCESS_TYPE_EVERYBODY"}, nil
+	case "nobody":
+		return &AccessConfig{Permission: "ACCESS_TYPE_NOBODY"}, nil
+	}
+
+	config := AccessConfig{Permission: "ACCESS_TYPE_ANY_OF_ADDRESSES"}
+
+	for _, item := range permission.Addresses {
+		address, found := d.getAccount(item)
+		if !found {
+			if !d.isAddress(item) {
+				err := fmt.Errorf("account not found")
+				d.logger.Err(err).Str("code", code).Str("account", item).Msg("")
+				return nil, err
+			}
+
+			address = item
+		}
+
+		config.Addresses = append(config.Addresses, address)
+	}
+
+	return &config, nil
+}
+
+// Flags returns the permission flags of wasm store
+func (c *AccessConfig) Flags() []string {
+	switch c.Permission {
+	case "ACCESS_TYPE_EVERYBODY":
+		return []string{"--instantiate-everybody", "true"}
+	case "ACCESS_TYPE_NOBODY":
+		return []string{"--instantiate-nobody", "true"}
+	}
+
+	return []string{"--instantiate-anyof-addresses", strings.Join(c.Addresses, ",")}
+}
+
+func (p *Permission) UnmarshalJSON(data []byte) error {
+	var addresses []string
+
+	err := json.Unmarshal(data, &addresses)
+	if err == nil {
+		if len(addresses) == 0 {
+			return fmt.Errorf("permission needs at least one address")
+		}
+
+		p.Type = "any_of_addresses"
+		p.Addresses = addresses
+		return nil
+	}
+
+	err = json.Unmarshal(data, &p.Type)
+	if err != nil {
+		return err
+	}
+
+	switch p.Type {
+	case "everybody", "nobody":
+		return nil
+	}
+
+	return fmt.Errorf("unknown permission: %s", p.Type)
+}
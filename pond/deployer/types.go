@@ -2,7 +2,20 @@ package deployer
 
 import "encoding/json"
 
+// ManifestNames are checked in order when deploying a directory
+var ManifestNames = []string{"manifest.yaml", "manifest.yml", "manifest.json"}
+
+// planKeys identify plan files in directories without a manifest
+var planKeys = []string{
+	"sender", "chain", "admin", "denoms", "codes", "permissions", "contracts",
+}
+
 type (
+	// Manifest lists the files of a directory in deployment order
+	Manifest struct {
+		Files []string `yaml:"files"`
+	}
+
 	Code struct {
 		Id       string `json:"id"`
 		Name     string `json:"name"`
@@ -12,10 +25,29 @@ type (
 	}
 
 	Denom struct {
-		Name  string `json:"name"`
-		Path  string `json:"path"`
-		Nonce string `json:"nonce"`
-		Mint  string `json:"mint"`
+		Name       string            `json:"name"`
+		Path       string            `json:"path"`
+		Nonce      string            `json:"nonce"`
+		Mint       string            `json:"mint"`
+		Recipients map[string]string `json:"recipients"` // account, plan contract or address -> amount
+		Metadata   *DenomMetadata    `json:"metadata"`
+		Transfers  []Transfer        `json:"transfers"`
+		IbcPath    map[string]string `json:"-"` // chain id -> ibc denom
+	}
+
+	DenomMetadata struct {
+		Display     string `json:"display"`
+		Exponent    uint32 `json:"exponent"`
+		Symbol      string `json:"symbol"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	// Transfer sends a newly created denom to another pond chain via IBC
+	Transfer struct {
+		Chain     string `json:"chain"` // ex.: terra2-1
+		Amount    string `json:"amount"`
+		Recipient string `json:"recipient"` // account name or address, defaults to the sender
 	}
 
 	Contract struct {
@@ -25,11 +57,18 @@ type (
 		Label    string                     `json:"label"`
 		Funds    string                     `json:"funds"`
 		Msg      map[string]json.RawMessage `json:"msg"`
+		Migrate  map[string]json.RawMessage `json:"migrate"`
+		Admin    string                     `json:"admin"` // account, plan contract, address or "none"
 		Creates  []Denom                    `json:"creates"`
 		Actions  []Action                   `json:"actions"`
 		Allocate bool                       `json:"allocate"`
+		Chain    string                     `json:"chain"` // chain id, ex.: terra2-1
 	}
 
+	// Stage is a list of contracts or an object with the chain of its
+	// contracts, ex.: {"chain": "terra2-1", "contracts": [...]}
+	Stage []Contract
+
 	Funds struct {
 		Denom  string `json:"denom"`
 		Amount string `json:"amount"`
@@ -40,4 +79,17 @@ type (
 		Msg      json.RawMessage `json:"msg"`
 		Funds    string          `json:"funds"`
 	}
+
+	// Permission is "everybody", "nobody" or a list of accounts and
+	// addresses allowed to instantiate a code
+	Permission struct {
+		Type      string
+		Addresses []string
+	}
+
+	// AccessConfig is the instantiate permission of MsgStoreCode
+	AccessConfig struct {
+		Permission string   `json:"permission"`
+		Addresses  []string `json:"addresses,omitempty"`
+	}
 )
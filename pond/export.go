@@ -0,0 +1,332 @@
+package pond
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ExportFormats are the supported formats of exported addresses
+var ExportFormats = []string{"env", "json", "ts", "go"}
+
+var identifierRegex = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// Export is a file that gets regenerated after every deployment
+type Export struct {
+	Format  string `json:"format"`
+	Output  string `json:"output"`
+	Package string `json:"package,omitempty"` // go only
+}
+
+type ExportChain struct {
+	RpcUrl  string `json:"rpc_url"`
+	ApiUrl  string `json:"api_url"`
+	GrpcUrl string `json:"grpc_url"`
+}
+
+// Addresses holds everything frontends and scripts need to talk to pond
+type Addresses struct {
+	Chains    map[string]ExportChain       `json:"chains"`
+	Contracts map[string]map[string]string `json:"contracts"` // by chain id
+	Codes     map[string]string            `json:"codes"`
+	Denoms    map[string]string            `json:"denoms"`
+	Ibc       map[string]string            `json:"ibc"` // trace -> ibc denom
+}
+
+// ExportAddresses writes the addresses in the given format, to stdout if no
+// output is set. Saved exports are regenerated after every deployment.
+func (p *Pond) ExportAddresses(export Export, save bool) error {
+	found := false
+	for _, format := range ExportFormats {
+		found = found || format == export.Format
+	}
+
+	if !found {
+		err := fmt.Errorf("format must be one of: %s",
+			strings.Join(ExportFormats, ", "))
+		return p.error(err)
+	}
+
+	// saved exports are regenerated from other directories
+	if export.Output != "" {
+		output, err := filepath.Abs(export.Output)
+		if err != nil {
+			return p.error(err)
+		}
+
+		export.Output = output
+	}
+
+	addresses, err := p.GetAddresses()
+	if err != nil {
+		return err
+	}
+
+	err = p.writeExport(export, addresses)
+	if err != nil {
+		return err
+	}
+
+	if !save || export.Output == "" {
+		return nil
+	}
+
+	exports := []Export{export}
+	for _, item := range p.config.Exports {
+		if item.Output != export.Output {
+			exports = append(exports, item)
+		}
+	}
+
+	p.config.Exports = exports
+
+	return p.SaveConfig()
+}
+
+// WriteExports regenerates all saved exports
+func (p *Pond) WriteExports() error {
+	if len(p.config.Exports) == 0 {
+		return nil
+	}
+
+	addresses, err := p.GetAddresses()
+	if err != nil {
+		return err
+	}
+
+	for _, export := range p.config.Exports {
+		err := p.writeExport(export, addresses)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Pond) GetAddresses() (Addresses, error) {
+	addresses := Addresses{
+		Chains:    map[string]ExportChain{},
+		Contracts: map[string]map[string]string{},
+		Codes:     map[string]string{},
+		Denoms:    map[string]string{},
+		Ibc:       map[string]string{},
+	}
+
+	for chainId, nodes := range p.info.Validators {
+		if len(nodes) == 0 {
+			continue
+		}
+
+		addresses.Chains[chainId] = ExportChain{
+			RpcUrl:  nodes[0].RpcUrl,
+			ApiUrl:  nodes[0].ApiUrl,
+			GrpcUrl: nodes[0].GrpcUrl,
+		}
+	}
+
+	// names are only unique per chain
+	for _, contract := range p.info.Contracts {
+		name := contract.Name
+		if name == "" {
+			name = contract.Label
+		}
+
+		// like in ListContracts, an empty chain is kujira-1
+		chainId := contract.Chain
+		if chainId == "" {
+			chainId = "kujira-1"
+		}
+
+		if addresses.Contracts[chainId] == nil {
+			addresses.Contracts[chainId] = map[string]string{}
+		}
+
+		addresses.Contracts[chainId][name] = contract.Address
+	}
+
+	for _, code := range p.info.Codes {
+		if code.Name != "" {
+			addresses.Codes[code.Name] = code.Id
+		}
+	}
+
+	for name, path := range p.info.Denoms {
+		addresses.Denoms[name] = path
+	}
+
+	if len(p.chains) == 0 {
+		return addresses, nil
+	}
+
+	api := p.chains[0].Nodes[0].Client()
+
+	traces, err := api.DenomTraces()
+	if err != nil {
+		p.logger.Warn().Err(err).Msg("skip ibc denoms")
+		return addresses, nil
+	}
+
+	for _, trace := range traces {
+		path := trace.Path + "/" + trace.BaseDenom
+		hash := sha256.Sum256([]byte(path))
+		addresses.Ibc[path] = fmt.Sprintf("ibc/%X", hash)
+	}
+
+	return addresses, nil
+}
+
+func (p *Pond) writeExport(export Export, addresses Addresses) error {
+	var data []byte
+	var err error
+
+	switch export.Format {
+	case "env":
+		data = exportEnv(addresses)
+	case "json":
+		data, err = json.MarshalIndent(addresses, "", "  ")
+		data = append(data, '\n')
+	case "ts":
+		data, err = exportTs(addresses)
+	case "go":
+		data, err = exportGo(addresses, export.Package)
+	}
+
+	if err != nil {
+		return p.error(err)
+	}
+
+	if export.Output == "" {
+		fmt.Print(string(data))
+		return nil
+	}
+
+	p.logger.Info().Str("file", export.Output).Msg("export addresses")
+
+	err = os.WriteFile(export.Output, data, 0o644)
+	if err != nil {
+		return p.error(err)
+	}
+
+	return nil
+}
+
+type exportSection struct {
+	name  string // go variable and env prefix
+	items map[string]string
+}
+
+// exportSections returns the flat maps in a fixed order, chain urls are
+// keyed by chain id and url type, contracts by chain id and name
+func exportSections(addresses Addresses) []exportSection {
+	chains := map[string]string{}
+	for chainId, chain := range addresses.Chains {
+		chains[chainId+"_rpc"] = chain.RpcUrl
+		chains[chainId+"_api"] = chain.ApiUrl
+		chains[chainId+"_grpc"] = chain.GrpcUrl
+	}
+
+	contracts := map[string]string{}
+	for chainId, items := range addresses.Contracts {
+		for name, address := range items {
+			contracts[chainId+"_"+name] = address
+		}
+	}
+
+	return []exportSection{
+		{"Chains", chains},
+		{"Contracts", contracts},
+		{"Codes", addresses.Codes},
+		{"Denoms", addresses.Denoms},
+		{"Ibc", addresses.Ibc},
+	}
+}
+
+func exportEnv(addresses Addresses) []byte {
+	var buffer bytes.Buffer
+
+	for _, section := range exportSections(addresses) {
+		prefix := strings.ToUpper(strings.TrimSuffix(section.name, "s"))
+
+		for _, key := range sortedKeys(section.items) {
+			fmt.Fprintf(
+				&buffer, "POND_%s_%s=%s\n", prefix, identifier(key),
+				section.items[key],
+			)
+		}
+	}
+
+	return buffer.Bytes()
+}
+
+func exportTs(addresses Addresses) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("// generated by pond, do not edit\n\n")
+
+	sections := []struct {
+		name  string
+		value any
+	}{
+		{"chains", addresses.Chains},
+		{"contracts", addresses.Contracts},
+		{"codes", addresses.Codes},
+		{"denoms", addresses.Denoms},
+		{"ibc", addresses.Ibc},
+	}
+
+	for _, section := range sections {
+		data, err := json.MarshalIndent(section.value, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(&buffer, "export const %s = %s as const;\n\n", section.name, data)
+	}
+
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
+func exportGo(addresses Addresses, pkg string) ([]byte, error) {
+	if pkg == "" {
+		pkg = "pond"
+	}
+
+	var buffer bytes.Buffer
+
+	fmt.Fprintf(&buffer, "// Code generated by pond. DO NOT EDIT.\n\npackage %s\n", pkg)
+
+	for _, section := range exportSections(addresses) {
+		fmt.Fprintf(&buffer, "\nvar %s = map[string]string{\n", section.name)
+
+		for _, key := range sortedKeys(section.items) {
+			fmt.Fprintf(&buffer, "\t%q: %q,\n", key, section.items[key])
+		}
+
+		buffer.WriteString("}\n")
+	}
+
+	return format.Source(buffer.Bytes())
+}
+
+// identifier turns names like "USK Controller" into USK_CONTROLLER
+func identifier(value string) string {
+	value = strings.Trim(identifierRegex.ReplaceAllString(value, "_"), "_")
+	return strings.ToUpper(value)
+}
+
+func sortedKeys(items map[string]string) []string {
+	keys := []string{}
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -5,6 +5,7 @@ type Chain struct {
 	Command string
 	Prefix  string
 	Home    string
+	Wasm    bool // supports cosmwasm contracts
 }
 
 var Chains = map[string]Chain{
@@ -13,6 +14,7 @@ var Chains = map[string]Chain{
 		Command: "kujirad",
 		Prefix:  "kujira",
 		Home:    ".kujira",
+		Wasm:    true,
 	},
 	"cosmoshub": {
 		Denom:   "uatom",
@@ -25,5 +27,6 @@ var Chains = map[string]Chain{
 		Command: "terrad",
 		Prefix:  "terra",
 		Home:    ".terra",
+		Wasm:    true,
 	},
 }
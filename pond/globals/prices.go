@@ -0,0 +1,13 @@
+package globals
+
+// StaticPrices are served by the feeders of offline setups, covering the
+// required oracle denoms
+var StaticPrices = map[string]string{
+	"BTC":   "60000.000000000000000000",
+	"ETH":   "3000.000000000000000000",
+	"KUJI":  "1.000000000000000000",
+	"STETH": "3000.000000000000000000",
+	"USDC":  "1.000000000000000000",
+	"USDT":  "1.000000000000000000",
+	"USK":   "1.000000000000000000",
+}
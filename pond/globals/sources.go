@@ -0,0 +1,8 @@
+package globals
+
+// Sources maps code source schemes to the API of their chain, ex.:
+// kaiyo-1://123 downloads code id 123 from kujira mainnet
+var Sources = map[string]string{
+	"kaiyo-1":   "https://rest.cosmos.directory/kujira",
+	"harpoon-4": "https://rest.cosmos.directory/kujiratestnet",
+}
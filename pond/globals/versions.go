@@ -9,3 +9,9 @@ var Versions = map[string]string{
 	"proxy":     "v1.0.0-2",
 	"horcrux":   "v3.3.0-1",
 }
+
+// Optimizers are the cosmwasm images used by pond build
+var Optimizers = map[string]string{
+	"workspace": "docker.io/cosmwasm/workspace-optimizer:0.15.1",
+	"contract":  "docker.io/cosmwasm/rust-optimizer:0.15.1",
+}
@@ -0,0 +1,166 @@
+package pond
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"pond/pond/client"
+)
+
+// SubmitProposal submits the proposal json and returns its id. All
+// validators vote with the given option, unless a vote distribution like
+// {"yes": 2, "no": 1} is set.
+func (p *Pond) SubmitProposal(
+	filename, option string, distribution map[string]int,
+) (string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		p.logger.Err(err).Msg("failed reading proposal json")
+		return "", err
+	}
+
+	return p.SubmitProposalJson(data, option, distribution)
+}
+
+func (p *Pond) SubmitProposalJson(
+	data []byte, option string, distribution map[string]int,
+) (string, error) {
+	chain := p.chains[0]
+
+	if len(distribution) == 0 && option != "" {
+		distribution = map[string]int{option: len(chain.Nodes)}
+	}
+
+	votes, err := chain.DistributeVotes(distribution)
+	if err != nil {
+		return "", err
+	}
+
+	return chain.SubmitProposal(data, votes)
+}
+
+func (p *Pond) ListProposals(status string) error {
+	if status != "" {
+		status = "PROPOSAL_STATUS_" + strings.ToUpper(status)
+	}
+
+	proposals, err := p.chains[0].GetProposals(status)
+	if err != nil {
+		return err
+	}
+
+	if len(proposals) == 0 {
+		return nil
+	}
+
+	fmt.Printf("%4s %-15s title\n", "id", "status")
+
+	for _, proposal := range proposals {
+		fmt.Printf(
+			"%4s %-15s %s\n", proposal.Id, shortStatus(proposal.Status),
+			proposal.Title,
+		)
+	}
+
+	return nil
+}
+
+func (p *Pond) ShowProposal(id string) error {
+	proposal, err := p.chains[0].GetProposal(id)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(proposal, "", "  ")
+	if err != nil {
+		return p.error(err)
+	}
+
+	fmt.Println(string(data))
+
+	return nil
+}
+
+// Vote votes with the validator of the given node number, 0 votes with all
+// validators
+func (p *Pond) Vote(proposal string, validator int, option string) error {
+	chain := p.chains[0]
+
+	if validator == 0 {
+		return chain.Vote(proposal, option)
+	}
+
+	return chain.VoteValidator(validator-1, proposal, option)
+}
+
+// Deposit deposits with the validator of the given node number
+func (p *Pond) Deposit(proposal string, validator int, amount string) error {
+	return p.chains[0].Deposit(validator-1, proposal, amount)
+}
+
+func (p *Pond) Tally(id string) error {
+	proposal, err := p.chains[0].GetProposal(id)
+	if err != nil {
+		return err
+	}
+
+	tally := proposal.FinalTallyResult
+
+	// the final result is only set after the voting period
+	if proposal.Status == "PROPOSAL_STATUS_VOTING_PERIOD" {
+		tally, err = p.chains[0].GetTally(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	printTally(tally)
+
+	return nil
+}
+
+// WaitProposal blocks until the proposal passed, got rejected or failed
+func (p *Pond) WaitProposal(id string) error {
+	proposal, events, err := p.chains[0].WaitProposal(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-8s %s\n", "status", shortStatus(proposal.Status))
+
+	printTally(proposal.FinalTallyResult)
+
+	if proposal.FailedReason != "" {
+		fmt.Printf("%-8s %s\n", "reason", proposal.FailedReason)
+	}
+
+	for _, event := range events {
+		for _, attribute := range event.Attributes {
+			if attribute.Key == "proposal_id" {
+				continue
+			}
+			fmt.Printf("%-8s %s: %s\n", "result", attribute.Key, attribute.Value)
+		}
+	}
+
+	if proposal.Status == "PROPOSAL_STATUS_FAILED" {
+		err := fmt.Errorf("proposal failed")
+		return p.error(err)
+	}
+
+	return nil
+}
+
+func printTally(tally client.Tally) {
+	fmt.Printf("%-8s %s\n", "yes", tally.Yes)
+	fmt.Printf("%-8s %s\n", "no", tally.No)
+	fmt.Printf("%-8s %s\n", "abstain", tally.Abstain)
+	fmt.Printf("%-8s %s\n", "veto", tally.NoWithVeto)
+}
+
+// shortStatus turns PROPOSAL_STATUS_VOTING_PERIOD into voting_period
+func shortStatus(status string) string {
+	return strings.ToLower(strings.TrimPrefix(status, "PROPOSAL_STATUS_"))
+}
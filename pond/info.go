@@ -16,9 +16,11 @@ type Account struct {
 }
 
 type Contract struct {
+	Name    string `json:"name,omitempty"` // plan name
 	Address string `json:"address"`
 	CodeId  string `json:"code_id"`
 	Label   string `json:"label"`
+	Chain   string `json:"chain,omitempty"` // ex.: terra2-1
 }
 
 type Info struct {
@@ -26,6 +28,7 @@ type Info struct {
 	Accounts   map[string]Account     `json:"accounts"`
 	Codes      []registry.Code        `json:"codes"`
 	Contracts  []Contract             `json:"contracts"`
+	Denoms     map[string]string      `json:"denoms"` // plan name -> path
 }
 
 func (p *Pond) LoadInfo() error {
@@ -194,9 +197,26 @@ func (i *Info) ListContracts() error {
 		return nil
 	}
 
-	fmt.Printf("%*s %-*s label\n", padding, "id", 65, "address")
+	// contracts deployed before chains were recorded are on kujira-1
+	padChain := 0
+	for i := range contracts {
+		if contracts[i].Chain == "" {
+			contracts[i].Chain = "kujira-1"
+		}
+
+		if len(contracts[i].Chain) > padChain {
+			padChain = len(contracts[i].Chain)
+		}
+	}
+
+	fmt.Printf(
+		"%-*s %*s %-*s label\n", padChain, "chain", padding, "id", 65, "address",
+	)
 
 	sort.Slice(contracts, func(i, j int) bool {
+		if contracts[i].Chain != contracts[j].Chain {
+			return contracts[i].Chain < contracts[j].Chain
+		}
 		if contracts[i].CodeId != contracts[j].CodeId {
 			if len(contracts[i].CodeId) == len(contracts[j].CodeId) {
 				return contracts[i].CodeId < contracts[j].CodeId
@@ -209,7 +229,8 @@ func (i *Info) ListContracts() error {
 
 	for _, contract := range contracts {
 		lines = append(lines, fmt.Sprintf(
-			"%*s %s %s", padding, contract.CodeId, contract.Address, contract.Label,
+			"%-*s %*s %s %s", padChain, contract.Chain, padding, contract.CodeId,
+			contract.Address, contract.Label,
 		))
 	}
 
@@ -224,11 +245,14 @@ func (i *Info) ListUrls() error {
 	for chain, nodes := range i.Validators {
 		for _, node := range nodes {
 			fmt.Printf("%s\n", node.Moniker)
-			fmt.Printf(" %-6s %s\n", "api", node.ApiUrl)
-			fmt.Printf(" %-6s %s\n", "rpc", node.RpcUrl)
-			fmt.Printf(" %-6s %s\n", "grpc", node.GrpcUrl)
+			if node.Version != "" {
+				fmt.Printf(" %-7s %s\n", "version", node.Version)
+			}
+			fmt.Printf(" %-7s %s\n", "api", node.ApiUrl)
+			fmt.Printf(" %-7s %s\n", "rpc", node.RpcUrl)
+			fmt.Printf(" %-7s %s\n", "grpc", node.GrpcUrl)
 			if chain == "kujira-1" {
-				fmt.Printf(" %-6s %s\n", "feeder", node.FeederUrl)
+				fmt.Printf(" %-7s %s\n", "feeder", node.FeederUrl)
 			}
 		}
 	}
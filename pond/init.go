@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 
@@ -11,6 +12,9 @@ import (
 	"pond/pond/chain/node"
 	"pond/pond/globals"
 	"pond/pond/templates"
+	"pond/utils"
+
+	"github.com/rs/zerolog"
 )
 
 func (p *Pond) Init(
@@ -37,7 +41,7 @@ func (p *Pond) Init(
 			}
 
 			if input == "y" || input == "yes" {
-				os.RemoveAll(p.home)
+				p.removeHome()
 				break
 			}
 		}
@@ -123,6 +127,13 @@ func (p *Pond) Init(
 		return err
 	}
 
+	if p.config.Offline {
+		err = p.checkOffline()
+		if err != nil {
+			return err
+		}
+	}
+
 	var mtx sync.Mutex
 	var wg sync.WaitGroup
 
@@ -140,7 +151,7 @@ func (p *Pond) Init(
 
 	wg.Add(1)
 	go func() {
-		p.proxy.Init(p.config.Namespace, local)
+		p.proxy.Init(p.config.Namespace, p.chains[0].Nodes[0].Local)
 		wg.Done()
 	}()
 
@@ -180,3 +191,74 @@ func (p *Pond) Init(
 
 	return nil
 }
+
+// checkOffline makes sure all codes and images are available locally
+func (p *Pond) checkOffline() error {
+	missing := p.deployer.UncachedCodes()
+	if len(missing) > 0 {
+		err := fmt.Errorf("codes not cached, run pond registry fetch")
+		p.logger.Err(err).Strs("codes", missing).Msg("")
+		return err
+	}
+
+	// static feeders use the proxy image
+	images := map[string]struct{}{}
+	apps := []string{"proxy"}
+
+	if len(p.chains) > 1 {
+		apps = append(apps, "relayer")
+	}
+
+	for _, chain := range p.chains {
+		for _, signer := range chain.Signers {
+			if signer == "horcrux" {
+				apps = append(apps, "horcrux")
+			}
+		}
+
+		for _, node := range chain.Nodes {
+			if !node.Local {
+				images[node.Image] = struct{}{}
+			}
+		}
+	}
+
+	for _, app := range apps {
+		version, err := utils.GetVersion(p.logger, app)
+		if err != nil {
+			return err
+		}
+
+		image := fmt.Sprintf("docker.io/%s/%s:%s", p.config.Namespace, app, version)
+		images[image] = struct{}{}
+	}
+
+	for image := range images {
+		command := []string{p.config.Command, "image", "inspect", image}
+
+		err := utils.Run(zerolog.Nop(), command)
+		if err != nil {
+			err := fmt.Errorf("image not available locally")
+			p.logger.Err(err).Str("image", image).Msg("")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeHome deletes all chain data, but keeps the code cache
+func (p *Pond) removeHome() {
+	entries, err := os.ReadDir(p.home)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.Name() == "cache" {
+			continue
+		}
+
+		os.RemoveAll(filepath.Join(p.home, entry.Name()))
+	}
+}
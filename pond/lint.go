@@ -0,0 +1,84 @@
+package pond
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"pond/pond/deployer"
+	"pond/pond/globals"
+	"pond/pond/templates"
+)
+
+// LintPlans checks plan files without a running chain and prints all
+// issues. The plans deployed on start are loaded first, as plan files
+// usually refer to their denoms and contracts.
+func (p *Pond) LintPlans(filenames []string) error {
+	codes := []string{}
+
+	if p.registry != nil {
+		for name := range p.registry.Data {
+			codes = append(codes, name)
+		}
+	} else {
+		for name := range globals.Registry {
+			codes = append(codes, name)
+		}
+	}
+
+	linter, err := deployer.NewLinter(codes)
+	if err != nil {
+		return p.error(err)
+	}
+
+	plans := p.config.Plans
+	if len(plans) == 0 {
+		plans, err = templates.GetPlans()
+		if err != nil {
+			return p.error(err)
+		}
+	}
+
+	for _, plan := range plans {
+		filename := "plan/" + plan + ".json"
+
+		data, err := templates.Templates.ReadFile(filename)
+		if err != nil {
+			return p.error(err)
+		}
+
+		linter.Add(filename, data)
+	}
+
+	files, err := deployer.ExpandFiles(p.logger, filenames)
+	if err != nil {
+		return err
+	}
+
+	for _, filename := range files {
+		if filepath.Ext(filename) == ".wasm" {
+			continue
+		}
+
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return p.error(err)
+		}
+
+		linter.Add(filename, data)
+	}
+
+	issues := linter.Lint()
+
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+
+	if len(issues) > 0 {
+		return fmt.Errorf("%d issues found", len(issues))
+	}
+
+	p.logger.Info().Msg("no issues found")
+
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"pond/pond/chain"
 	"pond/pond/chain/node"
 	"pond/pond/deployer"
+	"pond/pond/globals"
 	"pond/pond/registry"
 	"pond/pond/relayer"
 	"pond/pond/templates"
@@ -32,6 +33,29 @@ type Pond struct {
 }
 
 func NewPond(logLevel string) (Pond, error) {
+	pond, err := newPond(logLevel)
+	if err != nil {
+		return Pond{}, err
+	}
+
+	err = utils.Run(pond.logger, []string{"docker", "info"})
+	if err != nil {
+		return Pond{}, err
+	}
+
+	pond.LoadConfig()
+	pond.LoadInfo()
+
+	err = pond.init()
+	if err != nil {
+		return Pond{}, err
+	}
+
+	return pond, nil
+}
+
+// newPond sets up logging and the home directory
+func newPond(logLevel string) (Pond, error) {
 	level := zerolog.InfoLevel
 	switch logLevel {
 	case "debug":
@@ -59,22 +83,34 @@ func NewPond(logLevel string) (Pond, error) {
 		return Pond{}, err
 	}
 
-	err = utils.Run(logger, []string{"docker", "info"})
-	if err != nil {
-		return Pond{}, err
-	}
-
-	pond := Pond{
+	return Pond{
 		logger: logger,
 		home:   home + "/.pond",
 		info:   Info{},
 		config: Config{},
+	}, nil
+}
+
+// NewOfflinePond loads the config without docker and chains, for commands
+// that only read files, ex.: plan lint
+func NewOfflinePond(logLevel string) (Pond, error) {
+	pond, err := newPond(logLevel)
+	if err != nil {
+		return Pond{}, err
 	}
 
 	pond.LoadConfig()
-	pond.LoadInfo()
 
-	pond.init()
+	// the registry of an initialized pond knows all codes
+	filename := pond.home + "/registry.json"
+
+	_, err = os.Stat(filename)
+	if err == nil {
+		pond.registry, err = registry.NewRegistry(pond.logger, filename)
+		if err != nil {
+			return Pond{}, err
+		}
+	}
 
 	return pond, nil
 }
@@ -95,6 +131,7 @@ func (p *Pond) init() error {
 			p.config.Address,
 			config,
 			uint(i+1),
+			p.config.Offline,
 		)
 		if err != nil {
 			panic(err)
@@ -119,20 +156,51 @@ func (p *Pond) init() error {
 		return err
 	}
 
-	accounts := []string{}
+	accounts := map[string]string{}
 	for name, account := range p.info.Accounts {
-		if strings.HasPrefix(name, "test") || name == "deployer" {
-			accounts = append(accounts, account.Addresses["kujira"])
+		accounts[name] = account.Addresses["kujira"]
+	}
+
+	// account addresses on the other chains, used by ibc transfers
+	remotes := map[string]map[string]string{}
+	for _, chain := range p.chains[1:] {
+		remotes[chain.ChainId] = map[string]string{}
+		for name, account := range p.info.Accounts {
+			remotes[chain.ChainId][name] = account.Addresses[chain.Type]
 		}
 	}
 
+	// --api-url overrides the default mainnet source
+	sources := map[string]string{}
+	for scheme, url := range globals.Sources {
+		sources[scheme] = url
+	}
+
+	if p.config.ApiUrl != "" {
+		sources["kaiyo-1"] = p.config.ApiUrl
+	}
+
+	for scheme, url := range p.config.Sources {
+		sources[scheme] = url
+	}
+
 	p.deployer, err = deployer.NewDeployer(
-		p.logger, p.home, nodes[0], p.config.ApiUrl, accounts, p.registry,
+		p.logger, p.home, nodes[0], sources, accounts, remotes, p.registry,
 	)
 	if err != nil {
 		return p.error(err)
 	}
 
+	p.deployer.Offline = p.config.Offline
+
+	// plans can deploy contracts to other chains running cosmwasm
+	for _, chain := range p.chains[1:] {
+		config := globals.Chains[chain.Type]
+		if config.Wasm {
+			p.deployer.AddChain(chain.Nodes[0], config.Prefix)
+		}
+	}
+
 	p.proxy, err = NewProxy(p.logger, p.config.Command, p.config.Address)
 	if err != nil {
 		return err
@@ -208,7 +276,21 @@ func (p *Pond) Start() error {
 		return p.error(err)
 	}
 
-	p.chains[0].WaitBlocks(1)
+	err = p.chains[0].WaitBlocks(1)
+	if err != nil {
+		return p.error(err)
+	}
+
+	for _, chain := range p.chains {
+		failure, err := chain.Check()
+		if err != nil {
+			return err
+		}
+
+		if failure != nil {
+			return p.error(failure)
+		}
+	}
 
 	for _, plan := range p.config.Plans {
 		data, err := templates.Templates.ReadFile("plan/" + plan + ".json")
@@ -407,12 +489,25 @@ func (p *Pond) RemoveNetwork() error {
 	return nil
 }
 
-func (p *Pond) Deploy(filenames []string) error {
+// Deploy deploys wasm and plan files, from overrides the sender of the plans
+// and vars are their template variables. Dry runs only print the planned
+// changes.
+func (p *Pond) Deploy(
+	filenames []string, from string, vars map[string]string, dryRun bool,
+) error {
+	p.deployer.DryRun = dryRun
+	p.deployer.From = from
+	p.deployer.Vars = vars
+
 	err := p.deployer.Deploy(filenames)
 	if err != nil {
 		return err
 	}
 
+	if dryRun {
+		return nil
+	}
+
 	err = p.UpdateCodes()
 	if err != nil {
 		return err
@@ -423,7 +518,7 @@ func (p *Pond) Deploy(filenames []string) error {
 		return err
 	}
 
-	return nil
+	return p.WriteExports()
 }
 
 func (p *Pond) UpdateCodes() error {
@@ -444,38 +539,39 @@ func (p *Pond) UpdateContracts() error {
 		return err
 	}
 
-	known := map[string]struct{}{}
-	for _, contract := range p.info.Contracts {
-		known[contract.Address] = struct{}{}
+	known := map[string]int{}
+	for i, contract := range p.info.Contracts {
+		known[contract.Address] = i
 	}
 
-	info := []Contract{}
-
 	for _, contract := range contracts {
-		_, found := known[contract.Address]
+		// migrations change the code id
+		i, found := known[contract.Address]
 		if found {
+			p.info.Contracts[i].Name = contract.Name
+			p.info.Contracts[i].CodeId = contract.Code
+			p.info.Contracts[i].Chain = contract.Chain
 			continue
 		}
 
-		info = append(info, Contract{
+		p.info.Contracts = append(p.info.Contracts, Contract{
+			Name:    contract.Name,
 			Address: contract.Address,
 			CodeId:  contract.Code,
 			Label:   contract.Label,
+			Chain:   contract.Chain,
 		})
 	}
 
-	p.info.Contracts = append(p.info.Contracts, info...)
-	return p.SaveInfo()
-}
+	if p.info.Denoms == nil {
+		p.info.Denoms = map[string]string{}
+	}
 
-func (p *Pond) SubmitProposal(filename, option string) error {
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		p.logger.Err(err).Msg("failed reading proposal json")
-		return err
+	for name, denom := range p.deployer.Denoms {
+		p.info.Denoms[name] = denom.Path
 	}
 
-	return p.chains[0].SubmitProposal(data, option)
+	return p.SaveInfo()
 }
 
 func (p *Pond) error(err error) error {
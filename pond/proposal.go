@@ -0,0 +1,227 @@
+package pond
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"pond/pond/globals"
+	"pond/pond/templates"
+	"pond/utils"
+)
+
+// paramsMsgTypes maps modules to their MsgUpdateParams type
+var paramsMsgTypes = map[string]string{
+	"bank":         "/cosmos.bank.v1beta1.MsgUpdateParams",
+	"distribution": "/cosmos.distribution.v1beta1.MsgUpdateParams",
+	"gov":          "/cosmos.gov.v1.MsgUpdateParams",
+	"mint":         "/cosmos.mint.v1beta1.MsgUpdateParams",
+	"slashing":     "/cosmos.slashing.v1beta1.MsgUpdateParams",
+	"staking":      "/cosmos.staking.v1beta1.MsgUpdateParams",
+	"oracle":       "/kujira.oracle.MsgUpdateParams",
+	"denom":        "/kujira.denom.MsgUpdateParams",
+}
+
+var coinRegex = regexp.MustCompile(`^(\d+)([a-zA-Z][a-zA-Z0-9/:._-]*)$`)
+
+type proposalData struct {
+	Authority string
+	Deposit   string
+	Legacy    bool   // < sdk-50 with ibc-go < v8, ex.: no MsgRecoverClient
+	MsgType   string // params only
+	Params    string // params only
+}
+
+// NewProposal renders the proposal template with the given arguments
+func (p *Pond) NewProposal(name string, args map[string]string) ([]byte, error) {
+	content, err := templates.Templates.ReadFile("proposal/" + name + ".json")
+	if err != nil {
+		names, _ := templates.GetProposals()
+		err := fmt.Errorf(
+			"template not found, available: %s", strings.Join(names, ", "),
+		)
+		return nil, p.error(err)
+	}
+
+	chain := p.chains[0]
+	prefix := globals.Chains[chain.Type].Prefix
+
+	authority, err := utils.ModuleAddress(prefix, "gov")
+	if err != nil {
+		return nil, p.error(err)
+	}
+
+	api := chain.Nodes[0].Client()
+
+	minDeposit, err := api.MinDeposit()
+	if err != nil {
+		return nil, p.error(err)
+	}
+
+	deposit := []string{}
+	for _, coin := range minDeposit {
+		deposit = append(deposit, coin.Amount+coin.Denom)
+	}
+
+	version, err := api.SdkVersion()
+	if err != nil {
+		return nil, p.error(err)
+	}
+
+	minor, ok := utils.SdkMinor(version)
+
+	data := proposalData{
+		Authority: authority,
+		Deposit:   strings.Join(deposit, ","),
+		Legacy:    ok && minor < 50,
+	}
+
+	if name == "params" {
+		data.MsgType, data.Params, err = p.getParams(args)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	funcs := template.FuncMap{
+		"quote": func(value string) (string, error) {
+			data, err := json.Marshal(value)
+			return string(data), err
+		},
+		"arg": func(key string, fallback ...string) (string, error) {
+			value, found := args[key]
+			if found {
+				return value, nil
+			}
+
+			if len(fallback) > 0 {
+				return fallback[0], nil
+			}
+
+			return "", fmt.Errorf("missing argument: --set %s=...", key)
+		},
+		// accepts account names, contract labels and addresses
+		"address": func(value string) string {
+			account, found := p.info.Accounts[value]
+			if found {
+				return account.Addresses[chain.Type]
+			}
+
+			for _, contract := range p.info.Contracts {
+				if contract.Label == value {
+					return contract.Address
+				}
+			}
+
+			return value
+		},
+		"contract": func(value string) (string, error) {
+			return p.GetContractAddress(value)
+		},
+		"coins": func(value string) (string, error) {
+			coins := []map[string]string{}
+
+			for _, item := range strings.Split(value, ",") {
+				item = strings.TrimSpace(item)
+				if item == "" {
+					continue
+				}
+
+				matches := coinRegex.FindStringSubmatch(item)
+				if matches == nil {
+					return "", fmt.Errorf("invalid coin: %s", item)
+				}
+
+				coins = append(coins, map[string]string{
+					"denom": matches[2], "amount": matches[1],
+				})
+			}
+
+			data, err := json.Marshal(coins)
+			return string(data), err
+		},
+		"base64file": func(filename string) (string, error) {
+			data, err := os.ReadFile(filename)
+			if err != nil {
+				return "", err
+			}
+			return base64.StdEncoding.EncodeToString(data), nil
+		},
+	}
+
+	tmpl, err := template.New(name).Funcs(funcs).Parse(string(content))
+	if err != nil {
+		return nil, p.error(err)
+	}
+
+	var buffer bytes.Buffer
+
+	err = tmpl.Execute(&buffer, data)
+	if err != nil {
+		return nil, p.error(err)
+	}
+
+	var proposal bytes.Buffer
+
+	err = json.Indent(&proposal, buffer.Bytes(), "", "  ")
+	if err != nil {
+		return nil, p.error(err)
+	}
+
+	return proposal.Bytes(), nil
+}
+
+// getParams returns the msg type and the current params of a module,
+// merged with the "params" argument
+func (p *Pond) getParams(args map[string]string) (string, string, error) {
+	module, found := args["module"]
+	if !found {
+		err := fmt.Errorf("missing argument: --set module=...")
+		return "", "", p.error(err)
+	}
+
+	msgType, found := paramsMsgTypes[module]
+	if !found {
+		err := fmt.Errorf("module not supported")
+		p.logger.Err(err).Str("module", module).Msg("")
+		return "", "", err
+	}
+
+	output, err := p.chains[0].Nodes[0].Query(
+		[]string{module, "params", "--output", "json"},
+	)
+	if err != nil {
+		p.logger.Err(err).Str("module", module).Msg(string(output))
+		return "", "", err
+	}
+
+	// some modules wrap their params
+	var response map[string]json.RawMessage
+
+	err = json.Unmarshal(output, &response)
+	if err != nil {
+		return "", "", p.error(err)
+	}
+
+	params := output
+
+	wrapped, found := response["params"]
+	if found {
+		params = wrapped
+	}
+
+	override, found := args["params"]
+	if found {
+		params, err = utils.JsonMerge(params, []byte(override))
+		if err != nil {
+			return "", "", p.error(err)
+		}
+	}
+
+	return msgType, string(params), nil
+}
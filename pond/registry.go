@@ -1,5 +1,7 @@
 package pond
 
+import "fmt"
+
 func (p *Pond) ListRegistry() error {
 	return p.registry.List()
 }
@@ -17,11 +19,40 @@ func (p *Pond) ExportRegistry(filename string) error {
 	return p.registry.Export(filename)
 }
 
-func (p *Pond) ImportRegistry(filename string) error {
-	err := p.registry.Import(filename)
+func (p *Pond) ImportRegistry(filename string, merge bool) error {
+	err := p.registry.Import(filename, merge)
 	if err != nil {
 		return err
 	}
 
 	return p.UpdateCodes()
 }
+
+func (p *Pond) AddRegistry(name, source, checksum string) error {
+	return p.registry.Add(name, source, checksum)
+}
+
+func (p *Pond) RemoveRegistry(name string) error {
+	return p.registry.Remove(name)
+}
+
+// VerifyRegistry checks all registry codes against their sources and the
+// deployed codes
+func (p *Pond) VerifyRegistry() error {
+	if p.registry == nil {
+		err := fmt.Errorf("pond not initialized")
+		return p.error(err)
+	}
+
+	return p.deployer.VerifyCodes()
+}
+
+// FetchRegistry caches all registry codes for offline use
+func (p *Pond) FetchRegistry() error {
+	if p.registry == nil {
+		err := fmt.Errorf("pond not initialized")
+		return p.error(err)
+	}
+
+	return p.deployer.FetchCodes()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -13,6 +14,8 @@ import (
 	"github.com/rs/zerolog"
 )
 
+var nameRegex = regexp.MustCompile(`^[^@\s]+(@[^@\s]+)?$`)
+
 type Code struct {
 	Id       string `json:"id"`
 	Name     string `json:"name"`
@@ -50,30 +53,62 @@ func (r *Registry) error(err error) error {
 func (r *Registry) Load(filename string) error {
 	r.logger.Debug().Str("file", filename).Msg("load registry")
 
+	items, err := r.read(filename)
+	if err != nil {
+		return err
+	}
+
+	r.Data = items
+
+	return nil
+}
+
+func (r *Registry) read(filename string) (map[string]Code, error) {
 	data, err := os.ReadFile(filename)
 	if err != nil {
-		return r.error(err)
+		return nil, r.error(err)
 	}
 
 	var items map[string]Code
 	err = json.Unmarshal(data, &items)
 	if err != nil {
-		return r.error(err)
+		return nil, r.error(err)
 	}
 
-	r.Data = map[string]Code{}
+	codes := map[string]Code{}
 	for name, item := range items {
-		r.Data[name] = Code{
+		codes[name] = Code{
 			Source:   item.Source,
 			Checksum: strings.ToUpper(item.Checksum),
 		}
 	}
 
-	return nil
+	return codes, nil
 }
 
-func (r *Registry) Import(filename string) error {
-	r.Load(filename)
+// Import replaces the registry with the given file, or adds and overwrites
+// its codes if merge is set
+func (r *Registry) Import(filename string, merge bool) error {
+	items, err := r.read(filename)
+	if err != nil {
+		return err
+	}
+
+	for name := range items {
+		err := r.checkName(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !merge {
+		r.Data = map[string]Code{}
+	}
+
+	for name, item := range items {
+		r.Data[name] = item
+	}
+
 	return r.Save()
 }
 
@@ -144,34 +179,35 @@ func (r *Registry) Update(name string, args map[string]string) error {
 
 	newName, found := args["name"]
 	if found {
+		err := r.checkName(newName)
+		if err != nil {
+			return err
+		}
+
 		delete(r.Data, name)
 		name = newName
 	}
 
-	newSource, found := args["source"]
-	if found {
-		parts, err := url.Parse(newSource)
-		if err != nil {
-			return r.error(err)
-		}
+	newSource, sourceFound := args["source"]
+	newChecksum, checksumFound := args["checksum"]
+
+	if sourceFound {
+		// the old checksum belongs to the old source
+		item.Source = newSource
+		item.Checksum = ""
+	}
+
+	if checksumFound {
+		item.Checksum = newChecksum
+	}
 
-		switch parts.Scheme {
-		case "file":
-			data, err := os.ReadFile(parts.Path)
-			if err != nil {
-				return r.error(err)
-			}
-
-			item.Checksum = utils.Sha256(data)
-		case "kaiyo-1":
-			break
-		default:
-			err := fmt.Errorf("scheme not supported")
-			r.logger.Err(err).Str("scheme", parts.Scheme).Msg("")
+	if sourceFound || checksumFound {
+		checksum, err := r.checkSource(item.Source, item.Checksum)
+		if err != nil {
 			return err
 		}
 
-		item.Source = newSource
+		item.Checksum = checksum
 	}
 
 	r.Data[name] = item
@@ -179,12 +215,125 @@ func (r *Registry) Update(name string, args map[string]string) error {
 	return r.Save()
 }
 
+// Add registers a new code, versions are added as name@version
+func (r *Registry) Add(name, source, checksum string) error {
+	r.logger.Debug().Str("name", name).Msg("add registry item")
+
+	_, found := r.Data[name]
+	if found {
+		err := fmt.Errorf("code already registered")
+		r.logger.Err(err).Str("name", name).Msg("")
+		return err
+	}
+
+	err := r.checkName(name)
+	if err != nil {
+		return err
+	}
+
+	checksum, err = r.checkSource(source, checksum)
+	if err != nil {
+		return err
+	}
+
+	r.Data[name] = Code{
+		Source:   source,
+		Checksum: checksum,
+	}
+
+	return r.Save()
+}
+
+func (r *Registry) Remove(name string) error {
+	r.logger.Debug().Str("name", name).Msg("remove registry item")
+
+	_, found := r.Data[name]
+	if !found {
+		err := fmt.Errorf("code not registered")
+		r.logger.Err(err).Str("name", name).Msg("")
+		return err
+	}
+
+	delete(r.Data, name)
+
+	return r.Save()
+}
+
+// checkName allows names like kujira_fin and kujira_fin@v2
+func (r *Registry) checkName(name string) error {
+	if !nameRegex.MatchString(name) {
+		err := fmt.Errorf("invalid name")
+		r.logger.Err(err).Str("name", name).Msg("")
+		return err
+	}
+
+	return nil
+}
+
+// checkSource returns the checksum of the source. Local files are hashed,
+// downloads need a checksum, chain sources are verified on fetch.
+func (r *Registry) checkSource(source, checksum string) (string, error) {
+	checksum = strings.ToUpper(checksum)
+
+	parts, err := url.Parse(source)
+	if err != nil {
+		return "", r.error(err)
+	}
+
+	switch parts.Scheme {
+	case "file":
+		data, err := os.ReadFile(parts.Path)
+		if err != nil {
+			return "", r.error(err)
+		}
+
+		hash := utils.Sha256(data)
+		if checksum != "" && checksum != hash {
+			err := fmt.Errorf("checksum mismatch")
+			r.logger.Err(err).Str("source", source).Msg("")
+			return "", err
+		}
+
+		return hash, nil
+	case "https", "http":
+		if checksum == "" {
+			err := fmt.Errorf("checksum required")
+			r.logger.Err(err).Str("source", source).Msg("")
+			return "", err
+		}
+	case "":
+		err := fmt.Errorf("scheme not supported")
+		r.logger.Err(err).Str("source", source).Msg("")
+		return "", err
+	}
+
+	return checksum, nil
+}
+
+// Versions returns all registered names of the code, ex.: kujira_fin and
+// kujira_fin@v2
+func (r *Registry) Versions(name string) []string {
+	base := strings.SplitN(name, "@", 2)[0]
+	versions := []string{}
+
+	for key := range r.Data {
+		if key == base || strings.HasPrefix(key, base+"@") {
+			versions = append(versions, key)
+		}
+	}
+
+	sort.Strings(versions)
+
+	return versions
+}
+
 func (r *Registry) Get(name string) (Code, error) {
 	code, found := r.Data[name]
 	if !found {
 		msg := "code not found"
 		r.logger.Error().
 			Str("name", name).
+			Strs("versions", r.Versions(name)).
 			Msg(msg)
 		return Code{}, fmt.Errorf(msg)
 	}
@@ -9,7 +9,7 @@ import (
 	"pond/utils"
 
 	"github.com/rs/zerolog"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 type Relayer struct {
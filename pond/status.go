@@ -0,0 +1,51 @@
+package pond
+
+import (
+	"fmt"
+)
+
+func (p *Pond) Status() error {
+	var failed error
+
+	for _, chain := range p.chains {
+		states, _ := chain.GetStates()
+
+		fmt.Println(chain.ChainId)
+
+		for _, node := range chain.Nodes {
+			state, found := states[node.Moniker]
+			if !found {
+				fmt.Printf(" %-10s %s\n", node.Moniker, "unreachable")
+				continue
+			}
+
+			fmt.Printf(
+				" %-10s %-8d %s %s\n", node.Moniker, state.Height,
+				shorten(state.Hash), shorten(state.AppHash),
+			)
+		}
+
+		failure, err := chain.Check()
+		if err != nil {
+			return err
+		}
+
+		if failure != nil {
+			fmt.Printf(" %-10s %s\n", "status", failure.Error())
+			failed = failure
+			continue
+		}
+
+		fmt.Printf(" %-10s %s\n", "status", "ok")
+	}
+
+	return failed
+}
+
+func shorten(hash string) string {
+	if len(hash) < 16 {
+		return hash
+	}
+
+	return hash[:8] + "…" + hash[len(hash)-8:]
+}
@@ -5,7 +5,7 @@ import (
 	"strings"
 )
 
-//go:embed config genesis plan
+//go:embed config genesis plan proposal schema ui
 var Templates embed.FS
 
 func GetPlans() ([]string, error) {
@@ -26,6 +26,24 @@ func GetPlans() ([]string, error) {
 	return plans, nil
 }
 
+func GetProposals() ([]string, error) {
+	proposals := []string{}
+
+	entries, err := Templates.ReadDir("proposal")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		filename := entry.Name()
+		if strings.HasSuffix(filename, ".json") {
+			proposals = append(proposals, strings.TrimSuffix(filename, ".json"))
+		}
+	}
+
+	return proposals, nil
+}
+
 func GetChains() ([]string, error) {
 	chains := []string{}
 
@@ -0,0 +1,345 @@
+package pond
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"pond/pond/chain/node"
+	"pond/pond/client"
+	"pond/pond/registry"
+	"pond/pond/templates"
+	"pond/utils"
+)
+
+type dashboard struct {
+	Message    string
+	Error      string
+	Chains     []dashboardChain
+	Validators map[string][]node.Node
+	Accounts   []dashboardAccount
+	Codes      []registry.Code
+	Contracts  []Contract
+	Denoms     []string
+	Prices     map[string]string
+}
+
+type dashboardChain struct {
+	ChainId  string
+	Height   int64
+	Channels []client.Channel
+	Error    string
+}
+
+type dashboardAccount struct {
+	Name      string
+	Mnemonic  string
+	Addresses []dashboardAddress
+}
+
+type dashboardAddress struct {
+	ChainId  string
+	Address  string
+	Balances []client.Coin
+}
+
+// Ui serves the dashboard until the process is stopped
+func (p *Pond) Ui(port string) error {
+	tmpl, err := template.ParseFS(templates.Templates, "ui/index.html")
+	if err != nil {
+		return p.error(err)
+	}
+
+	// pond isn't safe for concurrent use
+	var mtx sync.Mutex
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+
+		mtx.Lock()
+		data := p.dashboard()
+		mtx.Unlock()
+
+		data.Message = r.URL.Query().Get("message")
+		data.Error = r.URL.Query().Get("error")
+
+		err := tmpl.Execute(w, data)
+		if err != nil {
+			p.logger.Err(err).Msg("")
+		}
+	})
+
+	actions := map[string]func(r *http.Request) (string, error){
+		"/send":     p.uiSend,
+		"/proposal": p.uiProposal,
+		"/vote":     p.uiVote,
+		"/deploy":   p.uiDeploy,
+	}
+
+	for path, action := range actions {
+		action := action
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+
+			if !sameOrigin(r) {
+				http.Error(w, "cross-origin request", http.StatusForbidden)
+				return
+			}
+
+			mtx.Lock()
+			message, err := action(r)
+			mtx.Unlock()
+
+			query := url.Values{}
+			if err != nil {
+				query.Set("error", err.Error())
+			} else {
+				query.Set("message", message)
+			}
+
+			http.Redirect(w, r, "/?"+query.Encode(), http.StatusSeeOther)
+		})
+	}
+
+	address := net.JoinHostPort(p.config.Address, port)
+
+	p.logger.Info().Str("url", "http://"+address).Msg("serve ui")
+
+	return http.ListenAndServe(address, mux)
+}
+
+// sameOrigin rejects form posts of other sites, browsers send the origin or
+// at least the referer with them
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		origin = r.Header.Get("Referer")
+	}
+
+	// not sent by a browser, ex.: curl
+	if origin == "" {
+		return true
+	}
+
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return parsed.Host == r.Host
+}
+
+func (p *Pond) dashboard() dashboard {
+	data := dashboard{
+		Validators: p.info.Validators,
+		Codes:      p.info.Codes,
+		Contracts:  p.info.Contracts,
+		Prices:     map[string]string{},
+	}
+
+	for _, chain := range p.chains {
+		c := chain.Nodes[0].Client()
+
+		info := dashboardChain{ChainId: chain.ChainId}
+
+		state, err := chain.Nodes[0].GetState()
+		if err != nil {
+			info.Error = err.Error()
+			data.Chains = append(data.Chains, info)
+			continue
+		}
+
+		info.Height = state.Height
+		info.Channels, _ = c.Channels()
+
+		data.Chains = append(data.Chains, info)
+	}
+
+	names := []string{}
+	for name := range p.info.Accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		account := dashboardAccount{
+			Name:     name,
+			Mnemonic: p.info.Accounts[name].Mnemonic,
+		}
+
+		for _, chain := range p.chains {
+			address, found := p.info.Accounts[name].Addresses[chain.Type]
+			if !found {
+				continue
+			}
+
+			c := chain.Nodes[0].Client()
+			balances, _ := c.Balances(address)
+
+			account.Addresses = append(account.Addresses, dashboardAddress{
+				ChainId:  chain.ChainId,
+				Address:  address,
+				Balances: balances,
+			})
+		}
+
+		data.Accounts = append(data.Accounts, account)
+	}
+
+	if len(p.chains) > 0 && p.chains[0].Type == "kujira" {
+		deployer := p.info.Accounts["deployer"].Addresses["kujira"]
+		data.Denoms, _ = p.deployer.GetDenomsFromCreator(deployer)
+	}
+
+	for _, chain := range p.chains {
+		for _, node := range chain.Nodes {
+			if node.FeederUrl == "" {
+				continue
+			}
+
+			output, err := utils.HttpGet(p.logger, node.FeederUrl)
+			if err != nil {
+				continue
+			}
+
+			var response struct {
+				Prices map[string]string `json:"prices"`
+			}
+
+			err = json.Unmarshal(output, &response)
+			if err != nil {
+				continue
+			}
+
+			for symbol, price := range response.Prices {
+				data.Prices[symbol] = price
+			}
+
+			break
+		}
+	}
+
+	return data
+}
+
+func (p *Pond) uiSend(r *http.Request) (string, error) {
+	chainId := r.FormValue("chain")
+	from := r.FormValue("from")
+	to := r.FormValue("to")
+	amount := r.FormValue("amount")
+
+	// accept account names as recipient
+	for _, chain := range p.chains {
+		if chain.ChainId != chainId {
+			continue
+		}
+
+		account, found := p.info.Accounts[to]
+		if found {
+			to = account.Addresses[chain.Type]
+		}
+	}
+
+	args := []string{
+		"bank", "send", from, to, amount, "--output", "json",
+	}
+
+	output, err := p.Tx(chainId, args)
+	if err != nil {
+		return "", errors.New(strings.TrimSpace(string(output)))
+	}
+
+	hash, err := utils.CheckTxResponse(output)
+	if err != nil {
+		return "", err
+	}
+
+	return "sent " + amount + " to " + to + ": " + hash, nil
+}
+
+func (p *Pond) uiProposal(r *http.Request) (string, error) {
+	filename, err := p.uiUpload(r, "proposal")
+	if err != nil {
+		return "", err
+	}
+
+	defer os.Remove(filename)
+
+	id, err := p.SubmitProposal(filename, r.FormValue("option"), nil)
+	if err != nil {
+		return "", err
+	}
+
+	return "proposal " + id + " submitted", nil
+}
+
+func (p *Pond) uiVote(r *http.Request) (string, error) {
+	proposal := r.FormValue("proposal")
+	option := r.FormValue("option")
+
+	err := p.Vote(proposal, 0, option)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("voted %s on proposal %s", option, proposal), nil
+}
+
+func (p *Pond) uiDeploy(r *http.Request) (string, error) {
+	filename, err := p.uiUpload(r, "plan")
+	if err != nil {
+		return "", err
+	}
+
+	defer os.Remove(filename)
+
+	err = p.Deploy([]string{filename}, "", nil, false)
+	if err != nil {
+		return "", err
+	}
+
+	return "plan deployed", nil
+}
+
+// uiUpload stores the uploaded file in a temp file and returns its name,
+// the extension is kept
+func (p *Pond) uiUpload(r *http.Request, field string) (string, error) {
+	file, header, err := r.FormFile(field)
+	if err != nil {
+		return "", p.error(err)
+	}
+
+	defer file.Close()
+
+	temp, err := os.CreateTemp("", field+"-*"+filepath.Ext(header.Filename))
+	if err != nil {
+		return "", p.error(err)
+	}
+
+	defer temp.Close()
+
+	_, err = io.Copy(temp, file)
+	if err != nil {
+		return "", p.error(err)
+	}
+
+	return temp.Name(), nil
+}
@@ -8,7 +8,7 @@ import (
 
 type Block struct{}
 
-func (p *Pond) Upgrade(version, binary string) error {
+func (p *Pond) Upgrade(version, binary, filename string) error {
 	if version == "" {
 		return fmt.Errorf("no version provided")
 	}
@@ -17,6 +17,19 @@ func (p *Pond) Upgrade(version, binary string) error {
 		return fmt.Errorf("upgrade only supported for local binaries")
 	}
 
+	var (
+		checks Checks
+		before Snapshot
+	)
+
+	if filename != "" {
+		var err error
+		checks, err = p.LoadChecks(filename)
+		if err != nil {
+			return err
+		}
+	}
+
 	chain := p.chains[0]
 	node := chain.Nodes[0]
 
@@ -64,57 +77,68 @@ func (p *Pond) Upgrade(version, binary string) error {
 
 	upgradeHeight := height + blocks + 10
 
-	prop := []byte(fmt.Sprintf(`
-	{
-		"messages": [
-			{
-				"@type": "/cosmos.upgrade.v1beta1.MsgSoftwareUpgrade",
-				"authority": "kujira10d07y265gmmuvt4z0w9aw880jnsr700jt23ame",
-				"plan": {
-					"name": "%s",
-					"time": "0001-01-01T00:00:00Z",
-					"height": "%d",
-					"info": "",
-					"upgraded_client_state": null
-				}
-			}
-		],
-		"metadata": "ipfs://CID",
-		"deposit": "100000000ukuji",
-		"title": "%s",
-		"summary": "%s"
-	}`, version, upgradeHeight, version, version))
+	prop, err := p.NewProposal("software-upgrade", map[string]string{
+		"name":   version,
+		"height": fmt.Sprint(upgradeHeight),
+	})
+	if err != nil {
+		return err
+	}
 
 	p.logger.Info().
 		Str("version", version).
 		Int64("height", upgradeHeight).
 		Msg("submit upgrade proposal")
 
-	chain.SubmitProposal(prop, "yes")
-
-	var remain int64 = -1
-	for height < upgradeHeight {
-		if upgradeHeight-height != remain {
-			remain = upgradeHeight - height
+	votes, err := chain.DistributeVotes(map[string]int{"yes": len(chain.Nodes)})
+	if err != nil {
+		return err
+	}
 
-			p.logger.Info().
-				Int64("blocks", remain).
-				Msg("waiting for upgrade height")
-		}
+	_, err = chain.SubmitProposal(prop, votes)
+	if err != nil {
+		return err
+	}
 
-		time.Sleep(time.Millisecond * 300)
-		height, err = chain.GetHeight()
+	if filename != "" {
+		before, err = p.RunChecks(checks)
 		if err != nil {
 			return err
 		}
 	}
 
+	err = chain.WaitForUpgrade(upgradeHeight)
+	if err != nil {
+		return p.error(err)
+	}
+
 	p.Stop()
 
 	p.config.Binary = binary
 
-	for i := range p.chains[0].Nodes {
+	// nodes running containers keep their version
+	for i, node := range p.chains[0].Nodes {
+		if !node.Local {
+			continue
+		}
 		p.chains[0].Nodes[i].Binary = binary
+		p.chains[0].Nodes[i].Version = binary
+	}
+
+	for num := range p.config.Chains[0].Binaries {
+		p.config.Chains[0].Binaries[num] = binary
+	}
+
+	validators := p.info.Validators[p.chains[0].ChainId]
+	for i := range validators {
+		if i < len(p.chains[0].Nodes) && p.chains[0].Nodes[i].Local {
+			validators[i].Version = binary
+		}
+	}
+
+	err = p.SaveInfo()
+	if err != nil {
+		return err
 	}
 
 	err = p.SaveConfig()
@@ -122,7 +146,19 @@ func (p *Pond) Upgrade(version, binary string) error {
 		return err
 	}
 
-	p.Start()
+	err = p.Start()
+	if err != nil {
+		return err
+	}
+
+	if filename == "" {
+		return nil
+	}
+
+	after, err := p.RunChecks(checks)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return p.CompareChecks(checks, before, after)
 }
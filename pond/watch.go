@@ -0,0 +1,246 @@
+package pond
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path"
+	"strings"
+	"sync"
+
+	"pond/pond/client"
+)
+
+type WatchFilter struct {
+	Chains    []string // ex.: ["kujira-1"]
+	Contracts []string // labels or addresses
+	Accounts  []string // ex.: ["deployer", "test0"]
+	Types     []string // event types, ex.: ["wasm", "ibc", "transfer"]
+	Json      bool
+}
+
+// eventGroups are shortcuts for the type filter
+var eventGroups = map[string][]string{
+	"wasm": {"wasm", "wasm-*", "instantiate", "execute", "migrate"},
+	"ibc": {
+		"send_packet", "recv_packet", "acknowledge_packet", "timeout_packet",
+		"write_acknowledgement", "fungible_token_packet",
+	},
+	"oracle": {"aggregate_vote", "aggregate_prevote", "exchange_rate_update"},
+}
+
+// hiddenEvents are skipped in the pretty output unless filtered for
+var hiddenEvents = []string{
+	"coin_spent", "coin_received", "transfer", "message", "tx", "commission",
+	"rewards", "mint", "burn",
+}
+
+type watchEvent struct {
+	ChainId string `json:"chain_id"`
+	client.Message
+}
+
+func (p *Pond) Watch(filter WatchFilter) error {
+	addresses := map[string]string{}
+
+	for name, account := range p.info.Accounts {
+		for _, address := range account.Addresses {
+			addresses[address] = name
+		}
+	}
+
+	for _, contract := range p.info.Contracts {
+		addresses[contract.Address] = contract.Label
+	}
+
+	// all addresses a tx has to contain one of
+	wanted := []string{}
+
+	for _, name := range filter.Contracts {
+		address, err := p.GetContractAddress(name)
+		if err != nil {
+			return err
+		}
+		wanted = append(wanted, address)
+	}
+
+	for _, name := range filter.Accounts {
+		account, found := p.info.Accounts[name]
+		if !found {
+			err := fmt.Errorf("account not found")
+			p.logger.Err(err).Str("account", name).Msg("")
+			return err
+		}
+
+		for _, address := range account.Addresses {
+			wanted = append(wanted, address)
+		}
+	}
+
+	types := []string{}
+	for _, name := range filter.Types {
+		group, found := eventGroups[name]
+		if found {
+			types = append(types, group...)
+			continue
+		}
+		types = append(types, name)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+
+	errs := make(chan error, len(p.chains))
+
+	for _, chain := range p.chains {
+		if len(filter.Chains) > 0 && !contains(filter.Chains, chain.ChainId) {
+			continue
+		}
+
+		c := chain.Nodes[0].Client()
+		chainId := chain.ChainId
+
+		p.logger.Info().Str("chain_id", chainId).Msg("watch")
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := c.Subscribe(ctx, func(msg client.Message) error {
+				if !matchAddresses(msg, wanted) {
+					return nil
+				}
+
+				if len(types) > 0 {
+					msg.Events = filterEvents(msg.Events, types)
+					if len(msg.Events) == 0 {
+						return nil
+					}
+				}
+
+				mtx.Lock()
+				defer mtx.Unlock()
+
+				event := watchEvent{ChainId: chainId, Message: msg}
+
+				if filter.Json {
+					data, err := json.Marshal(event)
+					if err != nil {
+						return err
+					}
+					fmt.Println(string(data))
+					return nil
+				}
+
+				printEvent(event, addresses, len(types) > 0)
+
+				return nil
+			}, client.QueryBlocks, client.QueryTxs)
+			if err != nil {
+				p.logger.Err(err).Str("chain_id", chainId).Msg("")
+				errs <- err
+				cancel()
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	return <-errs
+}
+
+func matchAddresses(msg client.Message, addresses []string) bool {
+	if len(addresses) == 0 {
+		return true
+	}
+
+	for _, event := range msg.Events {
+		for _, attribute := range event.Attributes {
+			if contains(addresses, attribute.Value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func filterEvents(events []client.Event, types []string) []client.Event {
+	filtered := []client.Event{}
+
+	for _, event := range events {
+		if matchType(event.Type, types) {
+			filtered = append(filtered, event)
+		}
+	}
+
+	return filtered
+}
+
+func matchType(name string, types []string) bool {
+	for _, pattern := range types {
+		matched, err := path.Match(pattern, name)
+		if err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func printEvent(
+	event watchEvent, addresses map[string]string, filtered bool,
+) {
+	switch event.Type {
+	case "NewBlock":
+		fmt.Printf(
+			"%s %d block txs=%d %s\n", event.ChainId, event.Height, event.Txs,
+			event.Time.Format("15:04:05.000"),
+		)
+	default:
+		fmt.Printf(
+			"%s %d tx %s code=%d\n", event.ChainId, event.Height,
+			event.Hash, event.Code,
+		)
+
+		if event.Code != 0 {
+			fmt.Printf("  %s\n", event.Log)
+		}
+	}
+
+	for _, e := range event.Events {
+		if !filtered && contains(hiddenEvents, e.Type) {
+			continue
+		}
+
+		attributes := []string{}
+		for _, attribute := range e.Attributes {
+			value := attribute.Value
+
+			name, found := addresses[value]
+			if found {
+				value = fmt.Sprintf("%s(%s)", name, value)
+			}
+
+			attributes = append(attributes, attribute.Key+"="+value)
+		}
+
+		fmt.Printf("  %s %s\n", e.Type, strings.Join(attributes, " "))
+	}
+}
+
+func contains(list []string, item string) bool {
+	for _, value := range list {
+		if value == item {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Bech32Encode encodes the data with the given human readable part,
+// ex.: kujira
+func Bech32Encode(prefix string, data []byte) (string, error) {
+	values, err := convertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	checksum := bech32Checksum(prefix, values)
+
+	var builder strings.Builder
+	builder.WriteString(prefix)
+	builder.WriteString("1")
+
+	for _, value := range append(values, checksum...) {
+		builder.WriteByte(bech32Charset[value])
+	}
+
+	return builder.String(), nil
+}
+
+// Bech32Decode returns the human readable part and data of an address
+func Bech32Decode(address string) (string, []byte, error) {
+	address = strings.ToLower(address)
+
+	index := strings.LastIndex(address, "1")
+	if index < 1 || index+7 > len(address) {
+		return "", nil, fmt.Errorf("invalid bech32 address: %s", address)
+	}
+
+	prefix := address[:index]
+
+	values := []byte{}
+	for _, char := range address[index+1:] {
+		value := strings.IndexRune(bech32Charset, char)
+		if value == -1 {
+			return "", nil, fmt.Errorf("invalid bech32 address: %s", address)
+		}
+		values = append(values, byte(value))
+	}
+
+	if bech32Polymod(append(bech32ExpandPrefix(prefix), values...)) != 1 {
+		return "", nil, fmt.Errorf("invalid bech32 checksum: %s", address)
+	}
+
+	data, err := convertBits(values[:len(values)-6], 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return prefix, data, nil
+}
+
+// ModuleAddress returns the address of a module account, ex.: gov
+func ModuleAddress(prefix, module string) (string, error) {
+	hash := sha256.Sum256([]byte(module))
+	return Bech32Encode(prefix, hash[:20])
+}
+
+// ContractAddress returns the instantiate2 address of a contract, like
+// wasmd's BuildContractAddressPredictable without init msg
+func ContractAddress(prefix string, checksum, creator, salt []byte) (string, error) {
+	key := []byte("wasm\x00")
+	for _, item := range [][]byte{checksum, creator, salt, {}} {
+		key = binary.BigEndian.AppendUint64(key, uint64(len(item)))
+		key = append(key, item...)
+	}
+
+	typ := sha256.Sum256([]byte("module"))
+	hash := sha256.Sum256(append(typ[:], key...))
+
+	return Bech32Encode(prefix, hash[:])
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := []uint32{
+		0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3,
+	}
+
+	chk := uint32(1)
+	for _, value := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(value)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+func bech32ExpandPrefix(prefix string) []byte {
+	values := []byte{}
+	for i := 0; i < len(prefix); i++ {
+		values = append(values, prefix[i]>>5)
+	}
+	values = append(values, 0)
+	for i := 0; i < len(prefix); i++ {
+		values = append(values, prefix[i]&31)
+	}
+	return values
+}
+
+func bech32Checksum(prefix string, data []byte) []byte {
+	values := append(bech32ExpandPrefix(prefix), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+
+	polymod := bech32Polymod(values) ^ 1
+
+	checksum := make([]byte, 6)
+	for i := range checksum {
+		checksum[i] = byte((polymod >> uint(5*(5-i))) & 31)
+	}
+
+	return checksum
+}
+
+func convertBits(data []byte, from, to uint, pad bool) ([]byte, error) {
+	acc := uint32(0)
+	bits := uint(0)
+	max := uint32(1<<to) - 1
+
+	result := []byte{}
+	for _, value := range data {
+		if uint32(value)>>from != 0 {
+			return nil, fmt.Errorf("invalid data range")
+		}
+
+		acc = acc<<from | uint32(value)
+		bits += from
+
+		for bits >= to {
+			bits -= to
+			result = append(result, byte(acc>>bits&max))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			result = append(result, byte(acc<<(to-bits)&max))
+		}
+	} else if bits >= from || acc<<(to-bits)&max != 0 {
+		return nil, fmt.Errorf("invalid padding")
+	}
+
+	return result, nil
+}
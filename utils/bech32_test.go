@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestBech32Decode(t *testing.T) {
+	// valid strings of bip-173
+	tests := []string{
+		"A12UEL5L",
+		"a12uel5l",
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+		"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w",
+	}
+
+	for _, test := range tests {
+		prefix, data, err := Bech32Decode(test)
+		if err != nil {
+			t.Errorf("Bech32Decode(%q): %s", test, err)
+			continue
+		}
+
+		encoded, err := Bech32Encode(prefix, data)
+		if err != nil {
+			t.Errorf("Bech32Encode(%q): %s", prefix, err)
+			continue
+		}
+
+		if encoded != strings.ToLower(test) {
+			t.Errorf("got %s, want %s", encoded, test)
+		}
+	}
+}
+
+func TestBech32DecodeErrors(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected string
+	}{
+		// checksum of a12uel5l with the last char changed
+		{"a12uel5m", "invalid bech32 checksum: a12uel5m"},
+		{"1pzry9x0s0muk", "invalid bech32 address: 1pzry9x0s0muk"},
+		{"pzry9x0s0muk", "invalid bech32 address: pzry9x0s0muk"},
+		{"a1qqqqq", "invalid bech32 address: a1qqqqq"},
+		{"a1b2uel5l", "invalid bech32 address: a1b2uel5l"},
+		{
+			"cosmos10d07y265gmmuvt4z0w9aw880jnsr700j6zn9km",
+			"invalid bech32 checksum: cosmos10d07y265gmmuvt4z0w9aw880jnsr700j6zn9km",
+		},
+	}
+
+	for _, test := range tests {
+		_, _, err := Bech32Decode(test.address)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("Bech32Decode(%q) = %v, want %s", test.address, err, test.expected)
+		}
+	}
+}
+
+func TestBech32RoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte{0xab}, 20)
+
+	address, err := Bech32Encode("kujira", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prefix, decoded, err := Bech32Decode(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if prefix != "kujira" || !bytes.Equal(decoded, data) {
+		t.Errorf("got %s %x", prefix, decoded)
+	}
+}
+
+func TestModuleAddress(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		module   string
+		expected string
+	}{
+		{"cosmos", "gov", "cosmos10d07y265gmmuvt4z0w9aw880jnsr700j6zn9kn"},
+		{"cosmos", "distribution", "cosmos1jv65s3grqf6v6jl3dp4t6c9t9rk99cd88lyufl"},
+		{"kujira", "gov", "kujira10d07y265gmmuvt4z0w9aw880jnsr700jt23ame"},
+	}
+
+	for _, test := range tests {
+		address, err := ModuleAddress(test.prefix, test.module)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if address != test.expected {
+			t.Errorf("ModuleAddress(%s, %s) = %s, want %s",
+				test.prefix, test.module, address, test.expected)
+		}
+	}
+}
+
+func TestContractAddress(t *testing.T) {
+	// instantiate2 vector of cosmwasm-std without msg
+	checksum, _ := hex.DecodeString(
+		"13a1fc994cc6d1c81b746ee0c0ff6f90043875e0bf1d9be6b7d779fc978dc2a5",
+	)
+	creator, _ := hex.DecodeString("9999999999aaaaaaaaaabbbbbbbbbbcccccccccc")
+	salt := []byte("a")
+
+	address, err := ContractAddress("purple", checksum, creator, salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "purple1t6r960j945lfv8mhl4mage2rg97w63xeynwrupum2s2l7em4lprs9ce5hk"
+	if address != expected {
+		t.Errorf("got %s, want %s", address, expected)
+	}
+}
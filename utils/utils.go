@@ -4,14 +4,17 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"io"
+	"math"
 	"net/http"
 	"os"
 	"os/exec"
 	"os/user"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,7 +22,7 @@ import (
 	"pond/pond/templates"
 
 	"github.com/rs/zerolog"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 )
 
 func RunB(logger zerolog.Logger, command []string, logfile string) error {
@@ -41,6 +44,21 @@ func RunO(logger zerolog.Logger, command []string) ([]byte, error) {
 	return run(logger, command, "", false, "")
 }
 
+// RunC returns stdout and stderr combined, ex.: for container logs
+func RunC(logger zerolog.Logger, command []string) ([]byte, error) {
+	logger.Trace().
+		Str("command", (strings.Join(command, " "))).
+		Msg("run command")
+
+	output, err := exec.Command(command[0], command[1:]...).CombinedOutput()
+	if err != nil {
+		logger.Err(err).Msg(string(output))
+		return output, err
+	}
+
+	return output, nil
+}
+
 func run(
 	logger zerolog.Logger,
 	command []string,
@@ -212,6 +230,31 @@ func GetVersion(logger zerolog.Logger, app string) (string, error) {
 	return version, nil
 }
 
+// SdkMinor returns the minor version of a cosmos-sdk version, ex.: 47 for
+// v0.47.5, versions >= v1 are treated as newer than all v0 versions
+func SdkMinor(version string) (int, bool) {
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) < 2 {
+		return 0, false
+	}
+
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, false
+	}
+
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, false
+	}
+
+	if major > 0 {
+		return math.MaxInt, true
+	}
+
+	return minor, true
+}
+
 func JsonMerge(data1, data2 []byte) ([]byte, error) {
 	var iface1, iface2 interface{}
 
@@ -368,20 +411,20 @@ func ListDiff(s1, s2 []string) []string {
 
 func CheckTxResponse(data []byte) (string, error) {
 	type Tx struct {
-		Code   int    `yaml:"code"`
-		Hash   string `yaml:"txhash"`
-		RawLog string `yaml:"raw_log"`
+		Code   int    `json:"code"`
+		Hash   string `json:"txhash"`
+		RawLog string `json:"raw_log"`
 	}
 
 	var tx Tx
 
-	err := yaml.Unmarshal(data, &tx)
+	err := json.Unmarshal(data, &tx)
 	if err != nil {
 		return tx.Hash, err
 	}
 
 	if tx.Code != 0 {
-		return tx.Hash, fmt.Errorf(tx.RawLog)
+		return tx.Hash, errors.New(tx.RawLog)
 	}
 
 	return tx.Hash, nil
@@ -413,3 +456,121 @@ func NewTxMsg(msgs []byte) ([]byte, error) {
 
 	return []byte(msg), nil
 }
+
+// YamlToJson converts yaml to json, non string keys are converted to
+// strings
+func YamlToJson(data []byte) ([]byte, error) {
+	var value interface{}
+
+	err := yaml.Unmarshal(data, &value)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err = convertYaml(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(value)
+}
+
+func convertYaml(value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			converted, err := convertYaml(item)
+			if err != nil {
+				return nil, err
+			}
+			value[key] = converted
+		}
+		return value, nil
+	case map[interface{}]interface{}:
+		result := map[string]interface{}{}
+		for key, item := range value {
+			name, ok := key.(string)
+			if !ok {
+				name = fmt.Sprint(key)
+			}
+
+			converted, err := convertYaml(item)
+			if err != nil {
+				return nil, err
+			}
+
+			result[name] = converted
+		}
+		return result, nil
+	case []interface{}:
+		for i, item := range value {
+			converted, err := convertYaml(item)
+			if err != nil {
+				return nil, err
+			}
+			value[i] = converted
+		}
+		return value, nil
+	}
+
+	return value, nil
+}
+
+// StripJsonComments removes // and /* */ comments and trailing commas
+func StripJsonComments(data []byte) []byte {
+	result := []byte{}
+
+	inString := false
+
+	for i := 0; i < len(data); i++ {
+		char := data[i]
+
+		if inString {
+			result = append(result, char)
+			if char == '\\' && i+1 < len(data) {
+				i++
+				result = append(result, data[i])
+			} else if char == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case char == '"':
+			inString = true
+		case char == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				result = append(result, '\n')
+			}
+			continue
+		case char == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				// keep line numbers intact
+				if data[i] == '\n' {
+					result = append(result, '\n')
+				}
+				i++
+			}
+			i++
+			continue
+		case char == ']' || char == '}':
+			// remove trailing comma
+			j := len(result) - 1
+			for j >= 0 && strings.ContainsRune(" \t\r\n", rune(result[j])) {
+				j--
+			}
+			if j >= 0 && result[j] == ',' {
+				result = append(result[:j], result[j+1:]...)
+			}
+		}
+
+		result = append(result, char)
+	}
+
+	return result
+}
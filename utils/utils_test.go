@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStripJsonComments(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "line comment",
+			input:    "{\"a\": 1 // one\n}",
+			expected: "{\"a\": 1 \n}",
+		},
+		{
+			name:     "comment at the end",
+			input:    "{\"a\": 1}\n// end",
+			expected: "{\"a\": 1}\n",
+		},
+		{
+			name:     "block comment",
+			input:    `{/* a */"a": 1}`,
+			expected: `{"a": 1}`,
+		},
+		{
+			name:     "multi-line block comment keeps lines",
+			input:    "{\n/* a\nb\n*/\"a\": 1}",
+			expected: "{\n\n\n\"a\": 1}",
+		},
+		{
+			name:     "comments inside strings",
+			input:    `{"url": "https://example.com", "glob": "/* not a comment */"}`,
+			expected: `{"url": "https://example.com", "glob": "/* not a comment */"}`,
+		},
+		{
+			name:     "escaped quotes",
+			input:    `{"a": "say \"//hi\"", "b": "\\"} // c`,
+			expected: `{"a": "say \"//hi\"", "b": "\\"} `,
+		},
+		{
+			name:     "trailing commas",
+			input:    "{\"a\": [1, 2,\n],\n\"b\": {\"c\": 3, },\n}",
+			expected: "{\"a\": [1, 2\n],\n\"b\": {\"c\": 3 }\n}",
+		},
+		{
+			name:     "commas inside strings",
+			input:    `{"a": ",]", "b": ",}"}`,
+			expected: `{"a": ",]", "b": ",}"}`,
+		},
+		{
+			name:     "trailing comma before a comment",
+			input:    "[1, // one\n]",
+			expected: "[1 \n]",
+		},
+	}
+
+	for _, test := range tests {
+		result := string(StripJsonComments([]byte(test.input)))
+		if result != test.expected {
+			t.Errorf("%s: got %q, want %q", test.name, result, test.expected)
+		}
+	}
+}
+
+func TestYamlToJson(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "string keys",
+			input:    "a: 1\nb: [x, y]",
+			expected: `{"a":1,"b":["x","y"]}`,
+		},
+		{
+			name:     "non string keys",
+			input:    "1: one\ntrue: yes\n2.5: half",
+			expected: `{"1":"one","2.5":"half","true":"yes"}`,
+		},
+		{
+			name:     "nested non string keys",
+			input:    "codes:\n  - 1: fin\n    2: bow\nmsg:\n  3: [a]",
+			expected: `{"codes":[{"1":"fin","2":"bow"}],"msg":{"3":["a"]}}`,
+		},
+	}
+
+	for _, test := range tests {
+		result, err := YamlToJson([]byte(test.input))
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		if string(result) != test.expected {
+			t.Errorf("%s: got %s, want %s", test.name, result, test.expected)
+		}
+	}
+
+	_, err := YamlToJson([]byte("a: [1"))
+	if err == nil {
+		t.Error("invalid yaml accepted")
+	}
+}
+
+func TestStripJsonCommentsValid(t *testing.T) {
+	input := `{
+		// plan
+		"contracts": [
+			{"name": "a", "label": "a // b"}, /* first */
+		],
+	}`
+
+	var plan map[string]any
+
+	err := json.Unmarshal(StripJsonComments([]byte(input)), &plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contracts := plan["contracts"].([]any)
+	label := contracts[0].(map[string]any)["label"]
+	if len(contracts) != 1 || label != "a // b" {
+		t.Errorf("got %v", plan)
+	}
+}